@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
+	// コンテナにタイムゾーン情報がなくても /schedule のタイムゾーンを解決できるようにする
+	_ "time/tzdata"
 
 	"github-discord-bot/internal/domain/repository"
 	"github-discord-bot/internal/infrastructure/crypto"
 	"github-discord-bot/internal/infrastructure/database"
+	"github-discord-bot/internal/infrastructure/github"
 	"github-discord-bot/internal/interface/handler"
 	"github-discord-bot/internal/usecase"
 
@@ -39,6 +47,12 @@ func main() {
 		log.Fatal("ENCRYPTION_KEY is required")
 	}
 
+	// GitHub Enterprise Server を利用する場合は API のベースURLを指定する
+	githubBaseURL, err := github.NormalizeBaseURL(os.Getenv("GITHUB_API_BASE_URL"))
+	if err != nil {
+		log.Fatalf("Invalid GITHUB_API_BASE_URL: %v", err)
+	}
+
 	// Initialize database
 	db, err := database.InitDB(databaseURL)
 	if err != nil {
@@ -55,9 +69,58 @@ func main() {
 	// Initialize repository
 	var userSettingRepo repository.UserSettingRepository = database.NewPostgresUserSettingRepository(db)
 
+	// Initialize GitHub response cache
+	responseCache, pruneResponseCache := newResponseCache(db)
+
+	// GitHub App (任意): 個人のトークンを登録していないユーザーはサーバーに紐付けたインストールで認証する
+	appAuth, err := newAppAuth(githubBaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize GitHub App: %v", err)
+	}
+	var appInstallationRepo repository.GuildAppInstallationRepository = database.NewPostgresGuildAppInstallationRepository(db)
+	var appTokenProvider usecase.InstallationTokenProvider
+	if appAuth != nil {
+		appTokenProvider = appAuth
+	}
+	appInstallationUsecase := usecase.NewAppInstallationUsecase(appInstallationRepo, appTokenProvider)
+
 	// Initialize usecases
-	settingUsecase := usecase.NewSettingUsecase(userSettingRepo, aesCrypto)
-	issuesUsecase := usecase.NewIssuesUsecase(userSettingRepo, aesCrypto)
+	settingUsecase := usecase.NewSettingUsecase(userSettingRepo, aesCrypto, githubBaseURL)
+
+	// OAuth (任意): /login のデバイス認可フローでトークンを取得し、期限が近づいたら自動で更新する
+	var deviceFlow usecase.DeviceFlowAuthenticator
+	if clientID := os.Getenv("GITHUB_OAUTH_CLIENT_ID"); clientID != "" {
+		deviceFlow = github.NewOAuthApp(clientID, os.Getenv("GITHUB_OAUTH_CLIENT_SECRET"), githubBaseURL)
+	}
+	loginUsecase := usecase.NewLoginUsecase(settingUsecase, deviceFlow, parseScopes(os.Getenv("GITHUB_OAUTH_SCOPES")))
+
+	issuesConfig := usecase.IssuesConfig{
+		GitHubBaseURL: githubBaseURL,
+		Concurrency:   getEnvInt("GITHUB_FETCH_CONCURRENCY", usecase.DefaultFetchConcurrency),
+		Strategy:      os.Getenv("GITHUB_ISSUES_STRATEGY"),
+		Backend:       os.Getenv("GITHUB_ISSUES_BACKEND"),
+		ResponseCache: responseCache,
+		MaxPages:      getEnvInt("GITHUB_MAX_PAGES_PER_COMMAND", 0),
+		RateLimits:    usecase.NewRateLimitStates(),
+
+		AppInstallations: appInstallationUsecase,
+		Login:            loginUsecase,
+	}
+	issuesUsecase := usecase.NewIssuesUsecase(userSettingRepo, aesCrypto, issuesConfig)
+	pullRequestsUsecase := usecase.NewPullRequestsUsecase(userSettingRepo, aesCrypto, issuesConfig)
+	var scheduledDigestRepo repository.ScheduledDigestRepository = database.NewPostgresScheduledDigestRepository(db)
+	scheduleUsecase := usecase.NewScheduleUsecase(scheduledDigestRepo)
+	var issueSnapshotRepo repository.IssueSnapshotRepository = database.NewPostgresIssueSnapshotRepository(db)
+	watchUsecase := usecase.NewWatchUsecase(issueSnapshotRepo)
+	var savedQueryRepo repository.SavedQueryRepository = database.NewPostgresSavedQueryRepository(db)
+	queryUsecase := usecase.NewQueryUsecase(savedQueryRepo, issueSnapshotRepo)
+
+	// GitHub webhook (任意): WEBHOOK_LISTEN_ADDR を指定すると配信を受け付け、/webhook で登録したチャンネルに投稿する
+	webhookListenAddr := os.Getenv("WEBHOOK_LISTEN_ADDR")
+	var webhookSubscriptionRepo repository.WebhookSubscriptionRepository = database.NewPostgresWebhookSubscriptionRepository(db)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookSubscriptionRepo, aesCrypto, webhookPayloadURL(webhookListenAddr, os.Getenv("WEBHOOK_PUBLIC_URL")))
+	var channelSubscriptionRepo repository.ChannelSubscriptionRepository = database.NewPostgresChannelSubscriptionRepository(db)
+	subscriptionUsecase := usecase.NewSubscriptionUsecase(channelSubscriptionRepo)
 
 	// Initialize Discord session
 	dg, err := discordgo.New("Bot " + discordToken)
@@ -66,7 +129,7 @@ func main() {
 	}
 
 	// Initialize handler
-	discordHandler := handler.NewDiscordHandler(settingUsecase, issuesUsecase)
+	discordHandler := handler.NewDiscordHandler(settingUsecase, issuesUsecase, pullRequestsUsecase, appInstallationUsecase, loginUsecase, scheduleUsecase, watchUsecase, webhookUsecase, subscriptionUsecase, queryUsecase)
 
 	// Register handlers
 	dg.AddHandler(discordHandler.HandleInteraction)
@@ -80,6 +143,8 @@ func main() {
 		log.Fatalf("Failed to open Discord connection: %v", err)
 	}
 	defer dg.Close()
+	// Discord 切断前に実行中の GitHub API 呼び出しを中断する
+	defer discordHandler.Close()
 
 	// Register commands
 	err = discordHandler.RegisterCommands(dg)
@@ -87,6 +152,25 @@ func main() {
 		log.Fatalf("Failed to register commands: %v", err)
 	}
 
+	// /schedule の定期ダイジェストを実行し、Postgres のレスポンスキャッシュの古い行も定期的に削除する
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go runDigestScheduler(schedulerCtx, scheduleUsecase, discordHandler, dg, pruneResponseCache)
+
+	// 登録済みトークンの定期確認 (期限切れ間近・取り消しを DM で通知)
+	if interval := getEnvInt("TOKEN_CHECK_INTERVAL_HOURS", 24); interval > 0 {
+		monitorCtx, stopMonitor := context.WithCancel(context.Background())
+		defer stopMonitor()
+		tokenMonitor := usecase.NewTokenMonitorUsecase(userSettingRepo, aesCrypto, handler.NewTokenAlertNotifier(dg), issuesConfig,
+			time.Duration(getEnvInt("TOKEN_EXPIRY_WARNING_DAYS", 7))*24*time.Hour)
+		go tokenMonitor.Run(monitorCtx, time.Duration(interval)*time.Hour)
+	}
+
+	if webhookListenAddr != "" {
+		stopWebhookServer := startWebhookServer(webhookListenAddr, handler.NewWebhookHandler(webhookUsecase, subscriptionUsecase, dg))
+		defer stopWebhookServer()
+	}
+
 	fmt.Println("Bot is now running. Press CTRL-C to exit.")
 
 	// Wait for interrupt signal
@@ -94,3 +178,78 @@ func main() {
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
 }
+
+// newAppAuth は GITHUB_APP_ID と秘密鍵 (GITHUB_APP_PRIVATE_KEY または GITHUB_APP_PRIVATE_KEY_PATH) から GitHub App の認証を生成します。
+// GITHUB_APP_ID が未設定の場合は nil を返します。
+func newAppAuth(baseURL string) (*github.AppAuth, error) {
+	rawAppID := os.Getenv("GITHUB_APP_ID")
+	if rawAppID == "" {
+		return nil, nil
+	}
+	appID, err := strconv.ParseInt(rawAppID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GITHUB_APP_ID: %w", err)
+	}
+
+	// .env では改行を \n と書けるようにする
+	privateKey := []byte(strings.ReplaceAll(os.Getenv("GITHUB_APP_PRIVATE_KEY"), `\n`, "\n"))
+	if path := os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH"); len(privateKey) == 0 && path != "" {
+		privateKey, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GITHUB_APP_PRIVATE_KEY_PATH: %w", err)
+		}
+	}
+	if len(privateKey) == 0 {
+		return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_PATH is required when GITHUB_APP_ID is set")
+	}
+
+	return github.NewAppAuth(appID, privateKey, baseURL)
+}
+
+// newResponseCache は GITHUB_RESPONSE_CACHE に応じて GitHub API のレスポンスキャッシュを生成します。
+// memory (既定): メモリ上の LRU / postgres: Postgres に保存して再起動後も利用 / none: キャッシュしない
+// postgres の場合は起動時に古い行を削除し、定期的に削除する関数も返します（それ以外は nil）。
+func newResponseCache(db *sql.DB) (github.ResponseCache, func(context.Context)) {
+	switch mode := os.Getenv("GITHUB_RESPONSE_CACHE"); mode {
+	case "none":
+		return nil, nil
+	case "postgres":
+		cache := database.NewPostgresResponseCache(db)
+		maxAge := time.Duration(getEnvInt("GITHUB_RESPONSE_CACHE_TTL_DAYS", 7)) * 24 * time.Hour
+		prune := func(ctx context.Context) {
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			if deleted, err := cache.DeleteOlderThan(ctx, maxAge); err != nil {
+				log.Printf("Failed to prune GitHub response cache: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Pruned %d stale GitHub response cache entries", deleted)
+			}
+		}
+		prune(context.Background())
+		return cache, prune
+	case "", "memory":
+		return github.NewLRUCache(getEnvInt("GITHUB_RESPONSE_CACHE_MAX_MB", 64) << 20), nil
+	default:
+		log.Printf("Unknown GITHUB_RESPONSE_CACHE=%q, using memory", mode)
+		return github.NewLRUCache(getEnvInt("GITHUB_RESPONSE_CACHE_MAX_MB", 64) << 20), nil
+	}
+}
+
+// parseScopes はカンマまたは空白区切りの OAuth スコープを分割します（空の場合は nil）
+func parseScopes(raw string) []string {
+	return strings.Fields(strings.ReplaceAll(raw, ",", " "))
+}
+
+// getEnvInt は整数の環境変数を読み込みます。未設定または不正な値の場合は defaultValue を返します。
+func getEnvInt(name string, defaultValue int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using default %d", name, raw, defaultValue)
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github-discord-bot/internal/interface/handler"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// digestPollInterval は実行時刻を過ぎた定期ダイジェストを確認する間隔です
+	digestPollInterval = time.Minute
+	// digestTimeout は定期ダイジェスト1件あたりの実行時間の上限です
+	digestTimeout = 2 * time.Minute
+	// cachePruneInterval はレスポンスキャッシュの古い行を削除する間隔です
+	cachePruneInterval = time.Hour
+)
+
+// runDigestScheduler は ctx がキャンセルされるまで、実行時刻を過ぎた定期ダイジェストを実行します。
+// pruneCache が nil でない場合は cachePruneInterval ごとに呼び出します（起動時の削除は newResponseCache で行います）。
+func runDigestScheduler(ctx context.Context, schedules *usecase.ScheduleUsecase, h *handler.DiscordHandler, s *discordgo.Session, pruneCache func(context.Context)) {
+	ticker := time.NewTicker(digestPollInterval)
+	defer ticker.Stop()
+
+	lastPruned := time.Now()
+	for {
+		runDueDigests(ctx, schedules, h, s)
+		if pruneCache != nil && time.Since(lastPruned) >= cachePruneInterval {
+			pruneCache(ctx)
+			lastPruned = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDueDigests は実行時刻を過ぎた定期ダイジェストを順に実行します
+func runDueDigests(ctx context.Context, schedules *usecase.ScheduleUsecase, h *handler.DiscordHandler, s *discordgo.Session) {
+	now := time.Now()
+	digests, err := schedules.DueDigests(ctx, now)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to load scheduled digests: %v", err)
+		}
+		return
+	}
+
+	for _, digest := range digests {
+		if ctx.Err() != nil {
+			return
+		}
+		// 投稿に失敗しても同じ回を繰り返さないよう、先に次回の実行時刻を記録する
+		if err := schedules.MarkRun(ctx, digest, now); err != nil {
+			log.Printf("Failed to update scheduled digest #%d: %v", digest.ID, err)
+			continue
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, digestTimeout)
+		if err := h.DeliverDigest(runCtx, s, digest); err != nil {
+			log.Printf("Scheduled digest #%d failed: %v", digest.ID, err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/interface/handler"
+)
+
+const (
+	// webhookReadHeaderTimeout は webhook の受信サーバーがリクエストヘッダーを待つ時間の上限です
+	webhookReadHeaderTimeout = 10 * time.Second
+	// webhookShutdownTimeout はシャットダウン時に処理中の配信を待つ時間の上限です
+	webhookShutdownTimeout = 5 * time.Second
+)
+
+// webhookPayloadURL は WEBHOOK_PUBLIC_URL から GitHub に設定する Payload URL を組み立てます。
+// 受信サーバーを起動しない場合 (listenAddr が空) は空文字列を返します。
+func webhookPayloadURL(listenAddr, publicURL string) string {
+	if listenAddr == "" {
+		return ""
+	}
+	if publicURL == "" {
+		log.Println("WEBHOOK_PUBLIC_URL is not set; /webhook will show only the path of the payload URL")
+		return handler.WebhookPath
+	}
+	return strings.TrimSuffix(publicURL, "/") + handler.WebhookPath
+}
+
+// startWebhookServer は listenAddr で GitHub webhook の受信サーバーを起動し、停止する関数を返します
+func startWebhookServer(listenAddr string, webhookHandler http.Handler) func() {
+	mux := http.NewServeMux()
+	mux.Handle(handler.WebhookPath, webhookHandler)
+	server := &http.Server{
+		Addr:              listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: webhookReadHeaderTimeout,
+	}
+
+	go func() {
+		log.Printf("Webhook server listening on %s%s", listenAddr, handler.WebhookPath)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Webhook server stopped: %v", err)
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Failed to shut down webhook server: %v", err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// ChannelSubscription は /subscribe で登録したチャンネル単位のフィードです。
+// サーバーで受信した webhook の配信のうち、リポジトリ・イベント・ラベルに一致するものを ChannelID に投稿します。
+type ChannelSubscription struct {
+	ID                int64
+	GuildID           string
+	ChannelID         string
+	RepositoryPattern string   // owner/repo・owner/*・owner（除外リポジトリと同じ形式）
+	Events            []string // 投稿するイベント (issue_opened など)
+	Labels            []string // いずれかのラベルが付いたものだけを投稿する（空の場合は絞り込まない）
+	CreatedBy         string   // 登録した Discord ユーザー ID
+	CreatedAt         time.Time
+}
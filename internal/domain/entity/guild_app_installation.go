@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// GuildAppInstallation は Discord サーバーに紐付けた GitHub App のインストールです。
+// 個人のトークンを登録していないユーザーは、このインストールのアクセストークンで GitHub API を利用します。
+type GuildAppInstallation struct {
+	GuildID        string
+	InstallationID int64
+	AccountLogin   string // インストール先の GitHub ユーザー/Organization
+	LinkedBy       string // 紐付けを行った Discord ユーザー ID
+	UpdatedAt      time.Time
+}
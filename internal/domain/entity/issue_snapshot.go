@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// IssueSnapshot は変更検知のために記録した、前回取得時の Issue の一覧です。
+// Scope は assign または issues:<repository> で、同じユーザーの異なる対象を区別します。
+type IssueSnapshot struct {
+	GuildID   string
+	UserID    string
+	Scope     string
+	Issues    []SnapshotIssue
+	UpdatedAt time.Time
+}
+
+// SnapshotIssue は差分の判定に使う Issue の情報です
+type SnapshotIssue struct {
+	URL        string    `json:"url"` // Issue を一意に識別するキー
+	Repository string    `json:"repository"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Assignees  []string  `json:"assignees"`
+	Labels     []string  `json:"labels"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// SavedQuery は /query で保存した名前付きの /issues の条件です。
+// /query action:run と scope:query の定期ダイジェストで、保存した対象と絞り込み条件で Issue を取得します。
+type SavedQuery struct {
+	ID         int64
+	GuildID    string
+	UserID     string
+	Name       string
+	Repository string // owner/repo・owner・all
+	Filter     QueryFilter
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// QueryFilter は保存する /issues の絞り込み条件です。値は /issues のオプションと同じ形式です。
+// since は "7d" のような相対指定を実行時に解釈するため、入力した文字列のまま保存します。
+type QueryFilter struct {
+	Type          string   `json:"type,omitempty"`
+	State         string   `json:"state,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	ExcludeLabels []string `json:"exclude_labels,omitempty"`
+	Milestone     string   `json:"milestone,omitempty"`
+	Assignee      string   `json:"assignee,omitempty"`
+	Creator       string   `json:"creator,omitempty"`
+	Mentioned     string   `json:"mentioned,omitempty"`
+	Since         string   `json:"since,omitempty"`
+	Sort          string   `json:"sort,omitempty"`
+	Direction     string   `json:"direction,omitempty"`
+	GroupBy       string   `json:"group_by,omitempty"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// ScheduledDigest は /schedule で登録した定期ダイジェストです。
+// NextRunAt になると Scope に応じて /issues・/assign・/query action:run と同じ取得を行い、通知チャンネルに投稿します。
+type ScheduledDigest struct {
+	ID         int64
+	GuildID    string
+	UserID     string
+	ChannelID  string // 登録したチャンネル（通知チャンネルが未設定の場合の投稿先）
+	Scope      string // issues / assign / query
+	Repository string // Scope が issues の場合の対象 (owner/repo・owner・all)
+	QueryID    int64  // Scope が query の場合の保存した条件 (SavedQuery.ID)
+	QueryName  string // QueryID の条件の名前（読み込み時のみ設定されます）
+	Mode       string // full: すべての Issue を投稿 / changes: 前回との差分だけを投稿
+	CronExpr   string
+	Timezone   string // cron 式を評価するタイムゾーン (IANA 名)
+	NextRunAt  time.Time
+	LastRunAt  time.Time // 未実行の場合はゼロ値
+	CreatedAt  time.Time
+}
@@ -4,15 +4,25 @@ import "time"
 
 type UserSetting struct {
 	GuildID                     string
-	ChannelID                   string   // 最後に設定コマンドが実行されたチャンネル
+	ChannelID                   string // 最後に設定コマンドが実行されたチャンネル
 	UserID                      string
 	EncryptedToken              string
-	ExcludedRepositories        []string // Deprecated: use ExcludedIssuesRepositories and ExcludedAssignRepositories
+	EncryptedRefreshToken       string    // /login で取得したトークンのリフレッシュトークン（PAT の場合は空）
+	TokenExpiresAt              time.Time // アクセストークンの有効期限（期限がない場合はゼロ値）
+	TokenLogin                  string    // トークンの認証ユーザー（登録時に取得）
+	TokenScopes                 []string  // トークンのスコープ（判別できない場合は nil）
+	TokenCheckedAt              time.Time // トークンを最後に定期確認した日時
+	TokenInvalidAt              time.Time // トークンの取り消し・期限切れを検知した日時（有効な場合はゼロ値）
+	TokenExpiryNotifiedAt       time.Time // 期限切れが近いことを通知した日時
+	RefreshTokenExpiresAt       time.Time // リフレッシュトークンの有効期限（期限がない場合はゼロ値）
+	GitHubBaseURL               string    // トークンが属する GitHub API のベースURL（空の場合は既定値）
+	ExcludedRepositories        []string  // Deprecated: use ExcludedIssuesRepositories and ExcludedAssignRepositories
 	ExcludedIssuesRepositories  []string
 	ExcludedAssignRepositories  []string
 	NotificationChannelID       string // Deprecated: 共通通知チャンネル（all スコープ用）
 	NotificationIssuesChannelID string // /issues コマンド用通知チャンネル
 	NotificationAssignChannelID string // /assign コマンド用通知チャンネル
+	RenderMode                  string // 一覧の既定の表示形式（空の場合は rich）
 	UpdatedAt                   time.Time
 }
 
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// WebhookSubscription は GitHub webhook の配信を Discord のチャンネルに投稿する設定です。
+// RepositoryPattern に一致するリポジトリの配信のうち、署名を Secret で検証できたものだけを投稿します。
+type WebhookSubscription struct {
+	ID                int64
+	GuildID           string
+	ChannelID         string // すべてのイベントを投稿するチャンネル（空の場合は /subscribe のフィードにだけ投稿）
+	RepositoryPattern string // owner/repo・owner/*・owner（除外リポジトリと同じ形式）
+	EncryptedSecret   string // webhook の Secret（暗号化済み）
+	CreatedBy         string // 登録した Discord ユーザー ID
+	CreatedAt         time.Time
+}
+
+// PostsAllEvents はすべてのイベントを投稿するチャンネルが設定されているかを返します
+func (s *WebhookSubscription) PostsAllEvents() bool {
+	return s.ChannelID != ""
+}
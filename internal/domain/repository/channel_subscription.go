@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github-discord-bot/internal/domain/entity"
+)
+
+type ChannelSubscriptionRepository interface {
+	// Create は登録を保存し、採番した ID を subscription.ID に設定します
+	Create(ctx context.Context, subscription *entity.ChannelSubscription) error
+	FindByGuild(ctx context.Context, guildID string) ([]*entity.ChannelSubscription, error)
+	// FindByRepository は guildIDs のサーバーの登録のうち、owner/repo に一致するパターン (owner/repo・owner/*・owner) のものを返します
+	FindByRepository(ctx context.Context, guildIDs []string, fullName string) ([]*entity.ChannelSubscription, error)
+	// Delete はサーバーの登録を削除し、削除できたかを返します
+	Delete(ctx context.Context, guildID string, id int64) (bool, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"github-discord-bot/internal/domain/entity"
+)
+
+type GuildAppInstallationRepository interface {
+	Save(ctx context.Context, installation *entity.GuildAppInstallation) error
+	FindByGuild(ctx context.Context, guildID string) (*entity.GuildAppInstallation, error)
+	Delete(ctx context.Context, guildID string) error
+}
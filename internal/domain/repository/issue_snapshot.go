@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github-discord-bot/internal/domain/entity"
+)
+
+type IssueSnapshotRepository interface {
+	// Find は記録済みのスナップショットを返します。記録がない場合は nil を返します。
+	Find(ctx context.Context, guildID, userID, scope string) (*entity.IssueSnapshot, error)
+	Save(ctx context.Context, snapshot *entity.IssueSnapshot) error
+	// Delete はスナップショットを削除します。記録がない場合も nil を返します。
+	Delete(ctx context.Context, guildID, userID, scope string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github-discord-bot/internal/domain/entity"
+)
+
+type SavedQueryRepository interface {
+	// Save は同じ名前の条件があれば上書きし、なければ作成して、ID を query.ID に設定します
+	Save(ctx context.Context, query *entity.SavedQuery) error
+	FindByUser(ctx context.Context, guildID, userID string) ([]*entity.SavedQuery, error)
+	// FindByName は名前が一致する条件を返します（存在しない場合は nil）
+	FindByName(ctx context.Context, guildID, userID, name string) (*entity.SavedQuery, error)
+	// FindByID はユーザー自身の条件を返します（存在しない場合は nil）
+	FindByID(ctx context.Context, guildID, userID string, id int64) (*entity.SavedQuery, error)
+	// Delete はユーザー自身の条件を削除し、削除できたかを返します
+	Delete(ctx context.Context, guildID, userID, name string) (bool, error)
+}
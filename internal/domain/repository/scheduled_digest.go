@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+)
+
+type ScheduledDigestRepository interface {
+	// Create は定期ダイジェストを保存し、採番した ID を digest.ID に設定します
+	Create(ctx context.Context, digest *entity.ScheduledDigest) error
+	FindByUser(ctx context.Context, guildID, userID string) ([]*entity.ScheduledDigest, error)
+	// FindDue は NextRunAt が now 以前の定期ダイジェストを返します
+	FindDue(ctx context.Context, now time.Time) ([]*entity.ScheduledDigest, error)
+	UpdateRun(ctx context.Context, id int64, lastRunAt, nextRunAt time.Time) error
+	// Delete はユーザー自身の定期ダイジェストを削除し、削除できたかを返します
+	Delete(ctx context.Context, guildID, userID string, id int64) (bool, error)
+}
@@ -8,6 +8,11 @@ import (
 type UserSettingRepository interface {
 	Save(ctx context.Context, setting *entity.UserSetting) error
 	FindByGuildAndUser(ctx context.Context, guildID, userID string) (*entity.UserSetting, error)
+	// FindAllWithValidToken は無効化されていないトークンを登録している全ユーザーの設定を返します
+	FindAllWithValidToken(ctx context.Context) ([]*entity.UserSetting, error)
+	// UpdateTokenCheck はトークンの定期確認の結果を保存し、保存できたかを返します。
+	// 確認している間にトークンが登録し直された場合（EncryptedToken が一致しない場合）は保存せずに false を返します。
+	UpdateTokenCheck(ctx context.Context, setting *entity.UserSetting) (bool, error)
 	SaveNotificationChannelSetting(ctx context.Context, guildID, userID, scope, channelID string) error
 	GetNotificationChannels(ctx context.Context, guildID, userID string) (map[string]string, error)
 	ClearNotificationChannels(ctx context.Context, guildID, userID string) error
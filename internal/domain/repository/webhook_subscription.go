@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github-discord-bot/internal/domain/entity"
+)
+
+type WebhookSubscriptionRepository interface {
+	// Create は登録を保存し、採番した ID を subscription.ID に設定します
+	Create(ctx context.Context, subscription *entity.WebhookSubscription) error
+	FindByGuild(ctx context.Context, guildID string) ([]*entity.WebhookSubscription, error)
+	// FindByRepository は owner/repo に一致するパターン (owner/repo・owner/*・owner) の登録を全サーバーから返します
+	FindByRepository(ctx context.Context, fullName string) ([]*entity.WebhookSubscription, error)
+	// Delete はサーバーの登録を削除し、削除できたかを返します
+	Delete(ctx context.Context, guildID string, id int64) (bool, error)
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+
+	"github.com/lib/pq"
+)
+
+type PostgresChannelSubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresChannelSubscriptionRepository(db *sql.DB) repository.ChannelSubscriptionRepository {
+	return &PostgresChannelSubscriptionRepository{db: db}
+}
+
+// channelSubscriptionColumns は query で読み込む channel_subscriptions のカラムです
+const channelSubscriptionColumns = `id, guild_id, channel_id, repository_pattern, events, labels, created_by, created_at`
+
+func (r *PostgresChannelSubscriptionRepository) Create(ctx context.Context, subscription *entity.ChannelSubscription) error {
+	query := `
+		INSERT INTO channel_subscriptions (guild_id, channel_id, repository_pattern, events, labels, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	labels := subscription.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	return r.db.QueryRowContext(ctx, query,
+		subscription.GuildID,
+		subscription.ChannelID,
+		subscription.RepositoryPattern,
+		pq.Array(subscription.Events),
+		pq.Array(labels),
+		subscription.CreatedBy,
+		subscription.CreatedAt.UTC(),
+	).Scan(&subscription.ID)
+}
+
+func (r *PostgresChannelSubscriptionRepository) FindByGuild(ctx context.Context, guildID string) ([]*entity.ChannelSubscription, error) {
+	query := `SELECT ` + channelSubscriptionColumns + ` FROM channel_subscriptions WHERE guild_id = $1 ORDER BY id`
+	return r.query(ctx, query, guildID)
+}
+
+func (r *PostgresChannelSubscriptionRepository) FindByRepository(ctx context.Context, guildIDs []string, fullName string) ([]*entity.ChannelSubscription, error) {
+	// GitHub のリポジトリ名は大文字・小文字を区別しない
+	fullName = strings.ToLower(fullName)
+	owner, _, _ := strings.Cut(fullName, "/")
+	query := `SELECT ` + channelSubscriptionColumns + ` FROM channel_subscriptions
+		WHERE guild_id = ANY($1) AND LOWER(repository_pattern) IN ($2, $3, $4) ORDER BY id`
+	return r.query(ctx, query, pq.Array(guildIDs), fullName, owner+"/*", owner)
+}
+
+func (r *PostgresChannelSubscriptionRepository) Delete(ctx context.Context, guildID string, id int64) (bool, error) {
+	query := `DELETE FROM channel_subscriptions WHERE id = $1 AND guild_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, guildID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *PostgresChannelSubscriptionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.ChannelSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*entity.ChannelSubscription
+	for rows.Next() {
+		var subscription entity.ChannelSubscription
+		if err := rows.Scan(
+			&subscription.ID,
+			&subscription.GuildID,
+			&subscription.ChannelID,
+			&subscription.RepositoryPattern,
+			pq.Array(&subscription.Events),
+			pq.Array(&subscription.Labels),
+			&subscription.CreatedBy,
+			&subscription.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+)
+
+type PostgresGuildAppInstallationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresGuildAppInstallationRepository(db *sql.DB) repository.GuildAppInstallationRepository {
+	return &PostgresGuildAppInstallationRepository{db: db}
+}
+
+func (r *PostgresGuildAppInstallationRepository) Save(ctx context.Context, installation *entity.GuildAppInstallation) error {
+	query := `
+		INSERT INTO guild_app_installations (guild_id, installation_id, account_login, linked_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (guild_id)
+		DO UPDATE SET installation_id = EXCLUDED.installation_id,
+		              account_login = EXCLUDED.account_login,
+		              linked_by = EXCLUDED.linked_by,
+		              updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		installation.GuildID,
+		installation.InstallationID,
+		installation.AccountLogin,
+		installation.LinkedBy,
+		installation.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresGuildAppInstallationRepository) FindByGuild(ctx context.Context, guildID string) (*entity.GuildAppInstallation, error) {
+	query := `
+		SELECT guild_id, installation_id, account_login, linked_by, updated_at
+		FROM guild_app_installations
+		WHERE guild_id = $1
+	`
+	var installation entity.GuildAppInstallation
+	err := r.db.QueryRowContext(ctx, query, guildID).Scan(
+		&installation.GuildID,
+		&installation.InstallationID,
+		&installation.AccountLogin,
+		&installation.LinkedBy,
+		&installation.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &installation, nil
+}
+
+func (r *PostgresGuildAppInstallationRepository) Delete(ctx context.Context, guildID string) error {
+	query := `DELETE FROM guild_app_installations WHERE guild_id = $1`
+	_, err := r.db.ExecContext(ctx, query, guildID)
+	return err
+}
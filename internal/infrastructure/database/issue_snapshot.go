@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+)
+
+type PostgresIssueSnapshotRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresIssueSnapshotRepository(db *sql.DB) repository.IssueSnapshotRepository {
+	return &PostgresIssueSnapshotRepository{db: db}
+}
+
+func (r *PostgresIssueSnapshotRepository) Find(ctx context.Context, guildID, userID, scope string) (*entity.IssueSnapshot, error) {
+	query := `SELECT issues, updated_at FROM issue_snapshots WHERE guild_id = $1 AND user_id = $2 AND scope = $3`
+
+	snapshot := entity.IssueSnapshot{GuildID: guildID, UserID: userID, Scope: scope}
+	var issues []byte
+	err := r.db.QueryRowContext(ctx, query, guildID, userID, scope).Scan(&issues, &snapshot.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(issues, &snapshot.Issues); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *PostgresIssueSnapshotRepository) Save(ctx context.Context, snapshot *entity.IssueSnapshot) error {
+	issues := snapshot.Issues
+	if issues == nil {
+		issues = []entity.SnapshotIssue{}
+	}
+	body, err := json.Marshal(issues)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO issue_snapshots (guild_id, user_id, scope, issues, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (guild_id, user_id, scope)
+		DO UPDATE SET issues = EXCLUDED.issues,
+		              updated_at = EXCLUDED.updated_at
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		snapshot.GuildID,
+		snapshot.UserID,
+		snapshot.Scope,
+		string(body), // []byte は bytea として送信されるため文字列で渡す
+		snapshot.UpdatedAt.UTC(),
+	)
+	return err
+}
+
+func (r *PostgresIssueSnapshotRepository) Delete(ctx context.Context, guildID, userID, scope string) error {
+	query := `DELETE FROM issue_snapshots WHERE guild_id = $1 AND user_id = $2 AND scope = $3`
+	_, err := r.db.ExecContext(ctx, query, guildID, userID, scope)
+	return err
+}
@@ -26,17 +26,34 @@ func (r *PostgresUserSettingRepository) Save(ctx context.Context, setting *entit
 			user_id,
 			channel_id,
 			encrypted_token,
+			encrypted_refresh_token,
+			token_expires_at,
+			refresh_token_expires_at,
+			token_login,
+			token_scopes,
 			excluded_repositories,
 			excluded_issues_repositories,
 			excluded_assign_repositories,
+			github_base_url,
+			render_mode,
 			updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		ON CONFLICT (guild_id, user_id)
 		DO UPDATE SET encrypted_token = COALESCE(EXCLUDED.encrypted_token, user_settings.encrypted_token),
+		              encrypted_refresh_token = CASE WHEN EXCLUDED.encrypted_token IS NULL THEN user_settings.encrypted_refresh_token ELSE EXCLUDED.encrypted_refresh_token END,
+		              token_expires_at = CASE WHEN EXCLUDED.encrypted_token IS NULL THEN user_settings.token_expires_at ELSE EXCLUDED.token_expires_at END,
+		              refresh_token_expires_at = CASE WHEN EXCLUDED.encrypted_token IS NULL THEN user_settings.refresh_token_expires_at ELSE EXCLUDED.refresh_token_expires_at END,
+		              token_login = CASE WHEN EXCLUDED.encrypted_token IS NULL THEN user_settings.token_login ELSE EXCLUDED.token_login END,
+		              token_scopes = CASE WHEN EXCLUDED.encrypted_token IS NULL THEN user_settings.token_scopes ELSE EXCLUDED.token_scopes END,
+		              token_checked_at = CASE WHEN COALESCE(EXCLUDED.encrypted_token, user_settings.encrypted_token) IS DISTINCT FROM user_settings.encrypted_token THEN NULL ELSE user_settings.token_checked_at END,
+		              token_invalid_at = CASE WHEN COALESCE(EXCLUDED.encrypted_token, user_settings.encrypted_token) IS DISTINCT FROM user_settings.encrypted_token THEN NULL ELSE user_settings.token_invalid_at END,
+		              token_expiry_notified_at = CASE WHEN COALESCE(EXCLUDED.encrypted_token, user_settings.encrypted_token) IS DISTINCT FROM user_settings.encrypted_token THEN NULL ELSE user_settings.token_expiry_notified_at END,
+		              github_base_url = CASE WHEN EXCLUDED.encrypted_token IS NULL THEN user_settings.github_base_url ELSE EXCLUDED.github_base_url END,
 		              excluded_repositories = COALESCE(EXCLUDED.excluded_repositories, user_settings.excluded_repositories),
 		              excluded_issues_repositories = COALESCE(EXCLUDED.excluded_issues_repositories, user_settings.excluded_issues_repositories),
 		              excluded_assign_repositories = COALESCE(EXCLUDED.excluded_assign_repositories, user_settings.excluded_assign_repositories),
+		              render_mode = COALESCE(EXCLUDED.render_mode, user_settings.render_mode),
 		              channel_id = EXCLUDED.channel_id,
 		              updated_at = EXCLUDED.updated_at
 	`
@@ -45,9 +62,16 @@ func (r *PostgresUserSettingRepository) Save(ctx context.Context, setting *entit
 		setting.UserID,
 		setting.ChannelID,
 		nullStringIfEmpty(setting.EncryptedToken),
+		nullStringIfEmpty(setting.EncryptedRefreshToken),
+		nullTimeIfZero(setting.TokenExpiresAt),
+		nullTimeIfZero(setting.RefreshTokenExpiresAt),
+		nullStringIfEmpty(setting.TokenLogin),
+		nullArrayIfNil(setting.TokenScopes),
 		nullArrayIfNil(setting.ExcludedRepositories),
 		nullArrayIfNil(setting.ExcludedIssuesRepositories),
 		nullArrayIfNil(setting.ExcludedAssignRepositories),
+		nullStringIfEmpty(setting.GitHubBaseURL),
+		nullStringIfEmpty(setting.RenderMode),
 		setting.UpdatedAt,
 	)
 	return err
@@ -60,6 +84,14 @@ func nullStringIfEmpty(s string) interface{} {
 	return s
 }
 
+// nullTimeIfZero は時刻がゼロ値の場合にNULLを返します
+func nullTimeIfZero(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
 // nullArrayIfNil は配列がnilの場合にNULLを返し、空配列の場合はそのまま返します
 // これにより、nilの場合は既存の値を保持（COALESCE）し、空配列の場合は明示的にクリアできます
 func nullArrayIfNil(arr []string) interface{} {
@@ -77,42 +109,158 @@ func ensureEmptyArrayNotNil(arr []string) []string {
 	return arr
 }
 
-func (r *PostgresUserSettingRepository) FindByGuildAndUser(ctx context.Context, guildID, userID string) (*entity.UserSetting, error) {
-	query := `
-		SELECT guild_id, user_id, channel_id, encrypted_token, excluded_repositories, excluded_issues_repositories, excluded_assign_repositories, updated_at
-		FROM user_settings
-		WHERE guild_id = $1 AND user_id = $2
-	`
+// userSettingColumns は scanUserSetting で読み込む user_settings のカラムです
+const userSettingColumns = `guild_id, user_id, channel_id, encrypted_token, encrypted_refresh_token, token_expires_at, refresh_token_expires_at, token_login, token_scopes, token_checked_at, token_invalid_at, token_expiry_notified_at, excluded_repositories, excluded_issues_repositories, excluded_assign_repositories, github_base_url, render_mode, updated_at`
+
+// rowScanner は *sql.Row と *sql.Rows の共通インターフェースです
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUserSetting は userSettingColumns の順に1行を読み込みます
+func scanUserSetting(row rowScanner) (*entity.UserSetting, error) {
 	var setting entity.UserSetting
 	var encryptedToken sql.NullString
-	err := r.db.QueryRowContext(ctx, query, guildID, userID).Scan(
+	var encryptedRefreshToken sql.NullString
+	var tokenExpiresAt sql.NullTime
+	var refreshTokenExpiresAt sql.NullTime
+	var tokenLogin sql.NullString
+	var tokenCheckedAt sql.NullTime
+	var tokenInvalidAt sql.NullTime
+	var tokenExpiryNotifiedAt sql.NullTime
+	var githubBaseURL sql.NullString
+	var renderMode sql.NullString
+	err := row.Scan(
 		&setting.GuildID,
 		&setting.UserID,
 		&setting.ChannelID,
 		&encryptedToken,
+		&encryptedRefreshToken,
+		&tokenExpiresAt,
+		&refreshTokenExpiresAt,
+		&tokenLogin,
+		pq.Array(&setting.TokenScopes),
+		&tokenCheckedAt,
+		&tokenInvalidAt,
+		&tokenExpiryNotifiedAt,
 		pq.Array(&setting.ExcludedRepositories),
 		pq.Array(&setting.ExcludedIssuesRepositories),
 		pq.Array(&setting.ExcludedAssignRepositories),
+		&githubBaseURL,
+		&renderMode,
 		&setting.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
 		return nil, err
 	}
 	if encryptedToken.Valid {
 		setting.EncryptedToken = encryptedToken.String
 	}
+	if encryptedRefreshToken.Valid {
+		setting.EncryptedRefreshToken = encryptedRefreshToken.String
+	}
+	if tokenExpiresAt.Valid {
+		setting.TokenExpiresAt = tokenExpiresAt.Time
+	}
+	if refreshTokenExpiresAt.Valid {
+		setting.RefreshTokenExpiresAt = refreshTokenExpiresAt.Time
+	}
+	if tokenLogin.Valid {
+		setting.TokenLogin = tokenLogin.String
+	}
+	if tokenCheckedAt.Valid {
+		setting.TokenCheckedAt = tokenCheckedAt.Time
+	}
+	if tokenInvalidAt.Valid {
+		setting.TokenInvalidAt = tokenInvalidAt.Time
+	}
+	if tokenExpiryNotifiedAt.Valid {
+		setting.TokenExpiryNotifiedAt = tokenExpiryNotifiedAt.Time
+	}
+	if githubBaseURL.Valid {
+		setting.GitHubBaseURL = githubBaseURL.String
+	}
+	if renderMode.Valid {
+		setting.RenderMode = renderMode.String
+	}
 	setting.ExcludedRepositories = ensureEmptyArrayNotNil(setting.ExcludedRepositories)
 	setting.ExcludedIssuesRepositories = ensureEmptyArrayNotNil(setting.ExcludedIssuesRepositories)
 	setting.ExcludedAssignRepositories = ensureEmptyArrayNotNil(setting.ExcludedAssignRepositories)
+	return &setting, nil
+}
 
-	if err := r.populateNotificationChannels(ctx, &setting); err != nil {
+func (r *PostgresUserSettingRepository) FindByGuildAndUser(ctx context.Context, guildID, userID string) (*entity.UserSetting, error) {
+	query := `SELECT ` + userSettingColumns + ` FROM user_settings WHERE guild_id = $1 AND user_id = $2`
+	setting, err := scanUserSetting(r.db.QueryRowContext(ctx, query, guildID, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	return &setting, nil
+	if err := r.populateNotificationChannels(ctx, setting); err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// FindAllWithValidToken は無効化されていないトークンを登録している全ユーザーの設定を返します
+func (r *PostgresUserSettingRepository) FindAllWithValidToken(ctx context.Context) ([]*entity.UserSetting, error) {
+	query := `SELECT ` + userSettingColumns + ` FROM user_settings WHERE encrypted_token IS NOT NULL AND token_invalid_at IS NULL`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []*entity.UserSetting
+	for rows.Next() {
+		setting, err := scanUserSetting(rows)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, setting := range settings {
+		if err := r.populateNotificationChannels(ctx, setting); err != nil {
+			return nil, err
+		}
+	}
+	return settings, nil
+}
+
+// UpdateTokenCheck はトークンの定期確認の結果（確認日時・無効化日時・期限切れ通知日時）を保存します。
+// 確認したトークン (setting.EncryptedToken) が登録し直されている場合は、新しいトークンの状態を上書きしないよう保存しません。
+func (r *PostgresUserSettingRepository) UpdateTokenCheck(ctx context.Context, setting *entity.UserSetting) (bool, error) {
+	query := `
+		UPDATE user_settings
+		SET token_checked_at = $3,
+		    token_invalid_at = $4,
+		    token_expiry_notified_at = $5
+		WHERE guild_id = $1 AND user_id = $2 AND encrypted_token = $6
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		setting.GuildID,
+		setting.UserID,
+		nullTimeIfZero(setting.TokenCheckedAt),
+		nullTimeIfZero(setting.TokenInvalidAt),
+		nullTimeIfZero(setting.TokenExpiryNotifiedAt),
+		setting.EncryptedToken,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
 }
 
 func (r *PostgresUserSettingRepository) ClearNotificationChannels(ctx context.Context, guildID, userID string) error {
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+)
+
+// openTestDB は TEST_DATABASE_URL のデータベースに一時的なスキーマを作成し、migrations を適用した接続を返します。
+// TEST_DATABASE_URL が設定されていない場合はテストをスキップします。
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// search_path を接続ごとに設定するため、接続を1本に固定する
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	schema := "test_" + strings.ToLower(rand.Text())
+	if _, err := db.ExecContext(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.ExecContext(context.Background(), `DROP SCHEMA `+schema+` CASCADE`) })
+	if _, err := db.ExecContext(ctx, `SET search_path TO `+schema); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "migrations", "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		migration, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.ExecContext(ctx, string(migration)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// TestUserSettingSaveKeepsTokenCheck は設定の変更ではトークンの定期確認の結果を残し、
+// トークンを登録し直した場合だけ消去することを確認します
+func TestUserSettingSaveKeepsTokenCheck(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresUserSettingRepository(db)
+
+	find := func() *entity.UserSetting {
+		t.Helper()
+		setting, err := repo.FindByGuildAndUser(ctx, "guild-1", "user-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return setting
+	}
+
+	err := repo.Save(ctx, &entity.UserSetting{
+		GuildID:        "guild-1",
+		UserID:         "user-1",
+		ChannelID:      "channel-1",
+		EncryptedToken: "encrypted-token-1",
+		TokenLogin:     "octocat",
+		UpdatedAt:      time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// トークンの定期確認で取り消しを検知した
+	setting := find()
+	invalidAt := time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)
+	setting.TokenCheckedAt = invalidAt
+	setting.TokenInvalidAt = invalidAt
+	if ok, err := repo.UpdateTokenCheck(ctx, setting); err != nil || !ok {
+		t.Fatalf("UpdateTokenCheck() = %v, %v", ok, err)
+	}
+
+	// 設定の変更は読み込んだ設定をそのまま保存する（同じトークンを渡す）
+	setting = find()
+	setting.ChannelID = "channel-2"
+	setting.RenderMode = "compact"
+	setting.ExcludedIssuesRepositories = []string{"acme/legacy"}
+	setting.UpdatedAt = time.Now()
+	if err := repo.Save(ctx, setting); err != nil {
+		t.Fatal(err)
+	}
+	if got := find(); !got.TokenInvalidAt.Equal(invalidAt) || !got.TokenCheckedAt.Equal(invalidAt) {
+		t.Errorf("after a setting change: checked = %v invalid = %v, want both %v", got.TokenCheckedAt, got.TokenInvalidAt, invalidAt)
+	}
+
+	// トークンを渡さない保存でも残す
+	err = repo.Save(ctx, &entity.UserSetting{GuildID: "guild-1", UserID: "user-1", ChannelID: "channel-3", UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := find(); !got.TokenInvalidAt.Equal(invalidAt) {
+		t.Errorf("after a save without a token: invalid = %v, want %v", got.TokenInvalidAt, invalidAt)
+	}
+
+	// トークンを登録し直すと消去する
+	err = repo.Save(ctx, &entity.UserSetting{
+		GuildID:        "guild-1",
+		UserID:         "user-1",
+		ChannelID:      "channel-3",
+		EncryptedToken: "encrypted-token-2",
+		TokenLogin:     "octocat",
+		UpdatedAt:      time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := find(); !got.TokenInvalidAt.IsZero() || !got.TokenCheckedAt.IsZero() {
+		t.Errorf("after re-registering: checked = %v invalid = %v, want both cleared", got.TokenCheckedAt, got.TokenInvalidAt)
+	}
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// PostgresResponseCache は GitHub API のレスポンスキャッシュを Postgres に保存します。
+// Bot を再起動してもキャッシュ (ETag) が失われないため、起動直後のリクエストでも 304 を受け取れます。
+type PostgresResponseCache struct {
+	db *sql.DB
+}
+
+func NewPostgresResponseCache(db *sql.DB) *PostgresResponseCache {
+	return &PostgresResponseCache{db: db}
+}
+
+func (c *PostgresResponseCache) Get(ctx context.Context, key string) (*github.CachedResponse, error) {
+	query := `SELECT etag, last_modified, link, body FROM github_response_cache WHERE cache_key = $1`
+
+	var response github.CachedResponse
+	var etag, lastModified, link sql.NullString
+	err := c.db.QueryRowContext(ctx, query, key).Scan(&etag, &lastModified, &link, &response.Body)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	response.ETag = etag.String
+	response.LastModified = lastModified.String
+	response.Link = link.String
+	return &response, nil
+}
+
+func (c *PostgresResponseCache) Set(ctx context.Context, key string, response *github.CachedResponse) error {
+	query := `
+		INSERT INTO github_response_cache (cache_key, etag, last_modified, link, body, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (cache_key)
+		DO UPDATE SET etag = EXCLUDED.etag,
+		              last_modified = EXCLUDED.last_modified,
+		              link = EXCLUDED.link,
+		              body = EXCLUDED.body,
+		              updated_at = EXCLUDED.updated_at
+	`
+	_, err := c.db.ExecContext(ctx, query,
+		key,
+		nullStringIfEmpty(response.ETag),
+		nullStringIfEmpty(response.LastModified),
+		nullStringIfEmpty(response.Link),
+		response.Body,
+		time.Now(),
+	)
+	return err
+}
+
+// DeleteOlderThan は maxAge より長く更新されていないキャッシュを削除し、削除件数を返します
+func (c *PostgresResponseCache) DeleteOlderThan(ctx context.Context, maxAge time.Duration) (int64, error) {
+	query := `DELETE FROM github_response_cache WHERE updated_at < $1`
+	result, err := c.db.ExecContext(ctx, query, time.Now().Add(-maxAge))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
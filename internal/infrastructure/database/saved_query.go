@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+)
+
+type PostgresSavedQueryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSavedQueryRepository(db *sql.DB) repository.SavedQueryRepository {
+	return &PostgresSavedQueryRepository{db: db}
+}
+
+// savedQueryColumns は query で読み込む saved_queries のカラムです
+const savedQueryColumns = `id, guild_id, user_id, name, repository, filter, created_at, updated_at`
+
+func (r *PostgresSavedQueryRepository) Save(ctx context.Context, savedQuery *entity.SavedQuery) error {
+	filter, err := json.Marshal(savedQuery.Filter)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO saved_queries (guild_id, user_id, name, repository, filter, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (guild_id, user_id, name)
+		DO UPDATE SET repository = EXCLUDED.repository,
+		              filter = EXCLUDED.filter,
+		              updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+	return r.db.QueryRowContext(ctx, query,
+		savedQuery.GuildID,
+		savedQuery.UserID,
+		savedQuery.Name,
+		savedQuery.Repository,
+		string(filter), // []byte は bytea として送信されるため文字列で渡す
+		savedQuery.CreatedAt.UTC(),
+		savedQuery.UpdatedAt.UTC(),
+	).Scan(&savedQuery.ID)
+}
+
+func (r *PostgresSavedQueryRepository) FindByUser(ctx context.Context, guildID, userID string) ([]*entity.SavedQuery, error) {
+	query := `SELECT ` + savedQueryColumns + ` FROM saved_queries WHERE guild_id = $1 AND user_id = $2 ORDER BY name`
+	return r.query(ctx, query, guildID, userID)
+}
+
+func (r *PostgresSavedQueryRepository) FindByName(ctx context.Context, guildID, userID, name string) (*entity.SavedQuery, error) {
+	query := `SELECT ` + savedQueryColumns + ` FROM saved_queries WHERE guild_id = $1 AND user_id = $2 AND name = $3`
+	return r.queryOne(ctx, query, guildID, userID, name)
+}
+
+func (r *PostgresSavedQueryRepository) FindByID(ctx context.Context, guildID, userID string, id int64) (*entity.SavedQuery, error) {
+	query := `SELECT ` + savedQueryColumns + ` FROM saved_queries WHERE guild_id = $1 AND user_id = $2 AND id = $3`
+	return r.queryOne(ctx, query, guildID, userID, id)
+}
+
+func (r *PostgresSavedQueryRepository) Delete(ctx context.Context, guildID, userID, name string) (bool, error) {
+	query := `DELETE FROM saved_queries WHERE guild_id = $1 AND user_id = $2 AND name = $3`
+	result, err := r.db.ExecContext(ctx, query, guildID, userID, name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// queryOne は1件だけを返します（存在しない場合は nil）
+func (r *PostgresSavedQueryRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*entity.SavedQuery, error) {
+	queries, err := r.query(ctx, query, args...)
+	if err != nil || len(queries) == 0 {
+		return nil, err
+	}
+	return queries[0], nil
+}
+
+func (r *PostgresSavedQueryRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.SavedQuery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queries []*entity.SavedQuery
+	for rows.Next() {
+		var savedQuery entity.SavedQuery
+		var filter []byte
+		if err := rows.Scan(
+			&savedQuery.ID,
+			&savedQuery.GuildID,
+			&savedQuery.UserID,
+			&savedQuery.Name,
+			&savedQuery.Repository,
+			&filter,
+			&savedQuery.CreatedAt,
+			&savedQuery.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(filter, &savedQuery.Filter); err != nil {
+			return nil, err
+		}
+		queries = append(queries, &savedQuery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+)
+
+type PostgresScheduledDigestRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresScheduledDigestRepository(db *sql.DB) repository.ScheduledDigestRepository {
+	return &PostgresScheduledDigestRepository{db: db}
+}
+
+// scheduledDigestColumns は query で読み込む scheduled_digests (d) と保存した条件の名前 (q) のカラムです
+const scheduledDigestColumns = `d.id, d.guild_id, d.user_id, d.channel_id, d.scope, d.repository, d.query_id, q.name, d.mode, d.cron_expr, d.timezone, d.next_run_at, d.last_run_at, d.created_at`
+
+// scheduledDigestTables は scheduledDigestColumns を読み込むテーブルです
+const scheduledDigestTables = `scheduled_digests d LEFT JOIN saved_queries q ON q.id = d.query_id`
+
+func (r *PostgresScheduledDigestRepository) Create(ctx context.Context, digest *entity.ScheduledDigest) error {
+	query := `
+		INSERT INTO scheduled_digests (guild_id, user_id, channel_id, scope, repository, query_id, mode, cron_expr, timezone, next_run_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+	// TIMESTAMP 型はタイムゾーンを持たないため UTC で保存する
+	return r.db.QueryRowContext(ctx, query,
+		digest.GuildID,
+		digest.UserID,
+		digest.ChannelID,
+		digest.Scope,
+		nullStringIfEmpty(digest.Repository),
+		sql.NullInt64{Int64: digest.QueryID, Valid: digest.QueryID > 0},
+		digest.Mode,
+		digest.CronExpr,
+		digest.Timezone,
+		digest.NextRunAt.UTC(),
+		digest.CreatedAt.UTC(),
+	).Scan(&digest.ID)
+}
+
+func (r *PostgresScheduledDigestRepository) FindByUser(ctx context.Context, guildID, userID string) ([]*entity.ScheduledDigest, error) {
+	query := `SELECT ` + scheduledDigestColumns + ` FROM ` + scheduledDigestTables + ` WHERE d.guild_id = $1 AND d.user_id = $2 ORDER BY d.id`
+	return r.query(ctx, query, guildID, userID)
+}
+
+func (r *PostgresScheduledDigestRepository) FindDue(ctx context.Context, now time.Time) ([]*entity.ScheduledDigest, error) {
+	// next_run_at は UTC の TIMESTAMP 型のため、引数も TIMESTAMP として比較する
+	// （TIMESTAMPTZ と比較するとセッションのタイムゾーンで変換され、ずれる）
+	query := `SELECT ` + scheduledDigestColumns + ` FROM ` + scheduledDigestTables + ` WHERE d.next_run_at <= $1::timestamp ORDER BY d.next_run_at`
+	return r.query(ctx, query, now.UTC())
+}
+
+func (r *PostgresScheduledDigestRepository) UpdateRun(ctx context.Context, id int64, lastRunAt, nextRunAt time.Time) error {
+	query := `UPDATE scheduled_digests SET last_run_at = $2, next_run_at = $3 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, lastRunAt.UTC(), nextRunAt.UTC())
+	return err
+}
+
+func (r *PostgresScheduledDigestRepository) Delete(ctx context.Context, guildID, userID string, id int64) (bool, error) {
+	query := `DELETE FROM scheduled_digests WHERE id = $1 AND guild_id = $2 AND user_id = $3`
+	result, err := r.db.ExecContext(ctx, query, id, guildID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *PostgresScheduledDigestRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.ScheduledDigest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var digests []*entity.ScheduledDigest
+	for rows.Next() {
+		var digest entity.ScheduledDigest
+		var repositoryName, queryName sql.NullString
+		var queryID sql.NullInt64
+		var lastRunAt sql.NullTime
+		if err := rows.Scan(
+			&digest.ID,
+			&digest.GuildID,
+			&digest.UserID,
+			&digest.ChannelID,
+			&digest.Scope,
+			&repositoryName,
+			&queryID,
+			&queryName,
+			&digest.Mode,
+			&digest.CronExpr,
+			&digest.Timezone,
+			&digest.NextRunAt,
+			&lastRunAt,
+			&digest.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if repositoryName.Valid {
+			digest.Repository = repositoryName.String
+		}
+		if queryID.Valid {
+			digest.QueryID = queryID.Int64
+			digest.QueryName = queryName.String
+		}
+		if lastRunAt.Valid {
+			digest.LastRunAt = lastRunAt.Time
+		}
+		digests = append(digests, &digest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
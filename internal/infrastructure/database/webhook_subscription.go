@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+)
+
+type PostgresWebhookSubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookSubscriptionRepository(db *sql.DB) repository.WebhookSubscriptionRepository {
+	return &PostgresWebhookSubscriptionRepository{db: db}
+}
+
+// webhookSubscriptionColumns は query で読み込む webhook_subscriptions のカラムです
+const webhookSubscriptionColumns = `id, guild_id, channel_id, repository_pattern, encrypted_secret, created_by, created_at`
+
+func (r *PostgresWebhookSubscriptionRepository) Create(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (guild_id, channel_id, repository_pattern, encrypted_secret, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return r.db.QueryRowContext(ctx, query,
+		subscription.GuildID,
+		subscription.ChannelID,
+		subscription.RepositoryPattern,
+		subscription.EncryptedSecret,
+		subscription.CreatedBy,
+		subscription.CreatedAt.UTC(),
+	).Scan(&subscription.ID)
+}
+
+func (r *PostgresWebhookSubscriptionRepository) FindByGuild(ctx context.Context, guildID string) ([]*entity.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE guild_id = $1 ORDER BY id`
+	return r.query(ctx, query, guildID)
+}
+
+func (r *PostgresWebhookSubscriptionRepository) FindByRepository(ctx context.Context, fullName string) ([]*entity.WebhookSubscription, error) {
+	// GitHub のリポジトリ名は大文字・小文字を区別しない
+	fullName = strings.ToLower(fullName)
+	owner, _, _ := strings.Cut(fullName, "/")
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions
+		WHERE LOWER(repository_pattern) IN ($1, $2, $3) ORDER BY id`
+	return r.query(ctx, query, fullName, owner+"/*", owner)
+}
+
+func (r *PostgresWebhookSubscriptionRepository) Delete(ctx context.Context, guildID string, id int64) (bool, error) {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1 AND guild_id = $2`
+	result, err := r.db.ExecContext(ctx, query, id, guildID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *PostgresWebhookSubscriptionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*entity.WebhookSubscription
+	for rows.Next() {
+		var subscription entity.WebhookSubscription
+		if err := rows.Scan(
+			&subscription.ID,
+			&subscription.GuildID,
+			&subscription.ChannelID,
+			&subscription.RepositoryPattern,
+			&subscription.EncryptedSecret,
+			&subscription.CreatedBy,
+			&subscription.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
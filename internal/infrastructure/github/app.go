@@ -0,0 +1,219 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidAppPrivateKey は GitHub App の秘密鍵を解析できない場合に返されます
+var ErrInvalidAppPrivateKey = errors.New("invalid GitHub App private key")
+
+const (
+	// appJWTLifetime は GitHub App の JWT の有効期間です（GitHub の上限は 10 分）
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew は GitHub とのクロックのずれを考慮して iat を過去にずらす時間です
+	appJWTClockSkew = 60 * time.Second
+	// installationTokenRefreshMargin はインストールアクセストークンを期限前に更新する余裕です
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+// Installation は GitHub App のインストール情報です
+type Installation struct {
+	ID                  int64  `json:"id"`
+	Account             User   `json:"account"`
+	RepositorySelection string `json:"repository_selection"` // all / selected
+}
+
+// AppAuth は GitHub App として認証し、インストールアクセストークンを発行します。
+// 発行したトークンは有効期限の少し前までインストールごとにキャッシュします。
+type AppAuth struct {
+	httpClient *http.Client
+	appID      int64
+	privateKey *rsa.PrivateKey
+	baseURL    string
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewAppAuth は GitHub App の ID と秘密鍵 (PEM) から AppAuth を生成します。
+// baseURL が空の場合は github.com (DefaultBaseURL) を利用します。
+func NewAppAuth(appID int64, privateKeyPEM []byte, baseURL string) (*AppAuth, error) {
+	key, err := parseAppPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &AppAuth{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		appID:      appID,
+		privateKey: key,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		tokens:     make(map[int64]installationToken),
+	}, nil
+}
+
+// parseAppPrivateKey は PKCS#1 / PKCS#8 形式の RSA 秘密鍵を解析します
+func parseAppPrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("%w: PEM block not found", ErrInvalidAppPrivateKey)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAppPrivateKey, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an RSA key", ErrInvalidAppPrivateKey)
+	}
+	return key, nil
+}
+
+// jwt は GitHub App として認証するための RS256 署名付き JWT を生成します
+func (a *AppAuth) jwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// doAppRequest は JWT で認証したリクエストを実行し、expectedStatus 以外の場合は GitHubError を返します
+func (a *AppAuth) doAppRequest(ctx context.Context, method, path string, expectedStatus int, result interface{}) error {
+	token, err := a.jwt(time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return &GitHubError{
+			StatusCode: resp.StatusCode,
+			Message:    getErrorMessage(resp.StatusCode),
+		}
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// GetInstallation はインストール情報を取得します（インストールIDの検証に使います）
+func (a *AppAuth) GetInstallation(ctx context.Context, installationID int64) (*Installation, error) {
+	var installation Installation
+	if err := a.doAppRequest(ctx, "GET", fmt.Sprintf("/app/installations/%d", installationID), http.StatusOK, &installation); err != nil {
+		return nil, err
+	}
+	return &installation, nil
+}
+
+// InstallationToken はインストールアクセストークンを返します。
+// キャッシュ済みのトークンが期限切れ間近でなければ、それを再利用します。
+func (a *AppAuth) InstallationToken(ctx context.Context, installationID int64) (string, error) {
+	a.mu.Lock()
+	cached, ok := a.tokens[installationID]
+	a.mu.Unlock()
+	if ok && time.Until(cached.ExpiresAt) > installationTokenRefreshMargin {
+		return cached.Token, nil
+	}
+
+	var token installationToken
+	if err := a.doAppRequest(ctx, "POST", fmt.Sprintf("/app/installations/%d/access_tokens", installationID), http.StatusCreated, &token); err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	a.tokens[installationID] = token
+	a.mu.Unlock()
+	return token.Token, nil
+}
+
+// installationRepositoriesResponse は /installation/repositories のレスポンスです
+type installationRepositoriesResponse struct {
+	TotalCount   int          `json:"total_count"`
+	Repositories []Repository `json:"repositories"`
+}
+
+func (c *Client) installationRepositoriesURL(page, perPage int) string {
+	return c.endpoint("/installation/repositories?page=%d&per_page=%d", page, perPage)
+}
+
+// GetAllInstallationRepositories はインストールアクセストークンでアクセスできる全リポジトリを取得します
+func (c *Client) GetAllInstallationRepositories(ctx context.Context) ([]Repository, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.installationRepositoriesURL(1, maxPerPage), func(page int) ([]Repository, *RateLimitInfo, pageLinks, error) {
+		var resp installationRepositoriesResponse
+		rateLimit, links, err := c.doPageRequest(ctx, c.installationRepositoriesURL(page, maxPerPage), &resp)
+		return resp.Repositories, rateLimit, links, err
+	})
+}
+
+// userInstallationsResponse は /user/installations のレスポンスです
+type userInstallationsResponse struct {
+	TotalCount    int            `json:"total_count"`
+	Installations []Installation `json:"installations"`
+}
+
+func (c *Client) userInstallationsURL(page, perPage int) string {
+	return c.endpoint("/user/installations?page=%d&per_page=%d", page, perPage)
+}
+
+// GetAllUserInstallations は認証ユーザーがアクセスできる GitHub App のインストールを取得します。
+// GitHub App の OAuth (ユーザーアクセストークン) でのみ利用でき、PAT や OAuth App のトークンでは 401 / 403 を返します。
+func (c *Client) GetAllUserInstallations(ctx context.Context) ([]Installation, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.userInstallationsURL(1, maxPerPage), func(page int) ([]Installation, *RateLimitInfo, pageLinks, error) {
+		var resp userInstallationsResponse
+		rateLimit, links, err := c.doPageRequest(ctx, c.userInstallationsURL(page, maxPerPage), &resp)
+		return resp.Installations, rateLimit, links, err
+	})
+}
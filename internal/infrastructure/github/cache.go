@@ -0,0 +1,135 @@
+package github
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// CachedResponse は条件付きリクエストのためにキャッシュするレスポンスです
+type CachedResponse struct {
+	ETag         string
+	LastModified string
+	Link         string // ページングの Link ヘッダー
+	Body         []byte
+}
+
+// ResponseCache は GET レスポンスを ETag / Last-Modified とともに保存するストアです。
+// 実装はメモリ上の LRU (LRUCache) と Postgres (database.PostgresResponseCache) があります。
+type ResponseCache interface {
+	// Get はキーに対応するレスポンスを返します。存在しない場合は nil を返します。
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+	Set(ctx context.Context, key string, response *CachedResponse) error
+}
+
+// Option は Client・GraphQLClient の生成オプションです
+type Option func(*clientOptions)
+
+// clientOptions は Client と GraphQLClient に共通の設定です
+type clientOptions struct {
+	cache      ResponseCache
+	retry      RetryPolicy
+	rateLimits *RateLimitState
+}
+
+func newClientOptions(opts []Option) clientOptions {
+	o := clientOptions{retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rateLimits == nil {
+		o.rateLimits = &RateLimitState{}
+	}
+	return o
+}
+
+// WithCache はレスポンスキャッシュを設定します。
+// 設定すると GET リクエストに If-None-Match / If-Modified-Since を付与し、
+// 304 Not Modified の場合はキャッシュ済みのレスポンスを利用します（304 は Rate Limit を消費しません）。
+// GraphQLClient はキャッシュを利用しません（クエリは POST のため）。
+func WithCache(cache ResponseCache) Option {
+	return func(c *clientOptions) {
+		c.cache = cache
+	}
+}
+
+// cacheKey はトークンとURLからキャッシュのキーを生成します。
+// レスポンスはトークンの権限によって異なるため、トークンごとに別のキーにします（トークンはハッシュ化して保存されます）。
+func cacheKey(token, url string) string {
+	sum := sha256.Sum256([]byte(token + "\n" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// DefaultLRUCacheMaxBytes は LRUCache の既定の最大サイズ (64MB) です
+const DefaultLRUCacheMaxBytes = 64 << 20
+
+// LRUCache はメモリ上に保持する LRU 方式のレスポンスキャッシュです。
+// 保持するレスポンスボディの合計サイズが maxBytes を超えると、最も古く参照されたものから破棄します。
+type LRUCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type lruEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewLRUCache は最大 maxBytes までレスポンスを保持する LRUCache を生成します。
+// maxBytes が 0 以下の場合は DefaultLRUCacheMaxBytes を利用します。
+func NewLRUCache(maxBytes int) *LRUCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultLRUCacheMaxBytes
+	}
+	return &LRUCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).response, nil
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, response *CachedResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 1件で上限を超えるレスポンスは保持しない
+	if len(response.Body) > c.maxBytes {
+		return nil
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		c.size += len(response.Body) - len(entry.response.Body)
+		entry.response = response
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.order.PushFront(&lruEntry{key: key, response: response})
+		c.size += len(response.Body)
+	}
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*lruEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= len(entry.response.Body)
+	}
+	return nil
+}
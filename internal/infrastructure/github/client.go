@@ -1,29 +1,112 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Client struct {
 	httpClient *http.Client
 	token      string
+	baseURL    string
+	clientOptions
 }
 
 const maxPerPage = 100
 
+// DefaultBaseURL は github.com の REST API のベースURLです
+const DefaultBaseURL = "https://api.github.com"
+
 type Issue struct {
-	Number     int         `json:"number"`
-	Title      string      `json:"title"`
-	HTMLURL    string      `json:"html_url"`
-	State      string      `json:"state"`
-	UpdatedAt  time.Time   `json:"updated_at"`
-	Labels     []Label     `json:"labels"`
-	Assignees  []User      `json:"assignees"`
-	Repository *Repository `json:"repository"`
+	Number        int         `json:"number"`
+	Title         string      `json:"title"`
+	HTMLURL       string      `json:"html_url"`
+	State         string      `json:"state"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	Comments      int         `json:"comments"`
+	User          *User       `json:"user"`
+	Labels        []Label     `json:"labels"`
+	Assignees     []User      `json:"assignees"`
+	Repository    *Repository `json:"repository"`
+	RepositoryURL string      `json:"repository_url"`
+	Milestone     *Milestone  `json:"milestone"`
+
+	// PullRequest は Pull Request の場合のみ設定されます（/issues 系エンドポイントは PR も返すため）
+	PullRequest *PullRequestRef `json:"pull_request"`
+
+	// 以下は GraphQL バックエンドでのみ設定されます
+	ProjectStatus      string              `json:"-"`
+	LinkedPullRequests []LinkedPullRequest `json:"-"`
+}
+
+// IsPullRequest は Issue 系エンドポイントが返した項目が Pull Request かどうかを返します
+func (i Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// HasLabel は Issue に指定したラベル（大文字・小文字は区別しない）が付いているかを返します
+func (i Issue) HasLabel(name string) bool {
+	for _, label := range i.Labels {
+		if strings.EqualFold(label.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// PullRequestRef は Issue 系エンドポイントのレスポンスに含まれる Pull Request 情報です
+type PullRequestRef struct {
+	URL      string     `json:"url"`
+	HTMLURL  string     `json:"html_url"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+// ItemType は Issue・Pull Request のどちらを対象にするかを表します
+type ItemType string
+
+const (
+	ItemTypeIssues       ItemType = "issues"
+	ItemTypePullRequests ItemType = "prs"
+	ItemTypeBoth         ItemType = "both"
+)
+
+// IncludesIssues は Issue を対象に含むかを返します
+func (t ItemType) IncludesIssues() bool {
+	return t != ItemTypePullRequests
+}
+
+// IncludesPullRequests は Pull Request を対象に含むかを返します
+func (t ItemType) IncludesPullRequests() bool {
+	return t != ItemTypeIssues
+}
+
+// Matches は項目が対象の種類に該当するかを返します
+func (t ItemType) Matches(issue Issue) bool {
+	if issue.IsPullRequest() {
+		return t.IncludesPullRequests()
+	}
+	return t.IncludesIssues()
+}
+
+// SearchQualifier は Search API で種類を絞り込む修飾子を返します（両方の場合は空）
+func (t ItemType) SearchQualifier() string {
+	switch t {
+	case ItemTypeIssues:
+		return "is:issue"
+	case ItemTypePullRequests:
+		return "is:pr"
+	default:
+		return ""
+	}
 }
 
 type Label struct {
@@ -31,6 +114,19 @@ type Label struct {
 	Color string `json:"color"`
 }
 
+type Milestone struct {
+	Number int        `json:"number"`
+	Title  string     `json:"title"`
+	DueOn  *time.Time `json:"due_on"`
+}
+
+// LinkedPullRequest は Issue をクローズする紐付き Pull Request です
+type LinkedPullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
+}
+
 type User struct {
 	Login string `json:"login"`
 }
@@ -40,7 +136,10 @@ type Repository struct {
 }
 
 type RateLimitInfo struct {
+	Limit     int
 	Remaining int
+	Used      int
+	Resource  string // core / search / graphql など
 	ResetAt   time.Time
 }
 
@@ -53,143 +152,302 @@ func (e *GitHubError) Error() string {
 	return fmt.Sprintf("GitHub API error: %d - %s", e.StatusCode, e.Message)
 }
 
-func NewClient(token string) *Client {
+// NewClient はGitHub APIクライアントを生成します。
+// baseURL が空の場合は github.com (DefaultBaseURL) を利用します。
+func NewClient(token, baseURL string, opts ...Option) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
 	return &Client{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		token:      token,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		token:         token,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		clientOptions: newClientOptions(opts),
 	}
 }
 
-// doRequest はGitHub APIへの汎用的なHTTPリクエストを実行します
-func (c *Client) doRequest(url string, result interface{}) (*RateLimitInfo, error) {
-	req, err := http.NewRequest("GET", url, nil)
+// BaseURL はクライアントが利用するAPIのベースURLを返します
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// endpoint はベースURLにパスを連結したリクエストURLを返します
+func (c *Client) endpoint(format string, args ...interface{}) string {
+	return c.baseURL + fmt.Sprintf(format, args...)
+}
+
+// ErrInvalidBaseURL はAPIのベースURLの形式が不正な場合に返されます
+var ErrInvalidBaseURL = errors.New("invalid GitHub API URL")
+
+// NormalizeBaseURL はユーザーが入力したAPIのベースURLを検証・正規化します。
+// GitHub Enterprise Server のホストのみが指定された場合は "/api/v3" を補完します。
+func NormalizeBaseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("%w: %v", ErrInvalidBaseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: unsupported scheme %s", ErrInvalidBaseURL, u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: host is empty", ErrInvalidBaseURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w: query and fragment are not allowed", ErrInvalidBaseURL)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	path := strings.TrimRight(u.Path, "/")
+	if strings.EqualFold(u.Host, "github.com") || strings.EqualFold(u.Host, "api.github.com") {
+		return DefaultBaseURL, nil
+	}
+	if path == "" {
+		path = "/api/v3"
+	}
 
-	resp, err := c.httpClient.Do(req)
+	return u.Scheme + "://" + u.Host + path, nil
+}
+
+// doRequest はGitHub APIへの汎用的なHTTPリクエストを実行します。
+// ctx がキャンセルされた場合は実行中のリクエストも中断されます。
+func (c *Client) doRequest(ctx context.Context, url string, result interface{}) (*RateLimitInfo, error) {
+	rateLimit, _, err := c.doPageRequest(ctx, url, result)
+	return rateLimit, err
+}
+
+// doPageRequest は doRequest と同様にリクエストを実行し、Link ヘッダーのページ情報も返します
+func (c *Client) doPageRequest(ctx context.Context, url string, result interface{}) (*RateLimitInfo, pageLinks, error) {
+	// キャッシュ済みのレスポンスがあれば条件付きリクエストにする
+	// （キャッシュの読み書きに失敗しても通常のリクエストとして続行する）
+	var (
+		key    string
+		cached *CachedResponse
+	)
+	if c.cache != nil {
+		key = cacheKey(c.token, url)
+		cached, _ = c.cache.Get(ctx, key)
+	}
+
+	// 5xx・セカンダリ Rate Limit などの一時的なエラーはバックオフしながらリトライする
+	resp, rateLimit, err := sendWithRetry(ctx, c.httpClient, c.retry, c.rateLimits, resourceForURL(url), true, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, err
+		return rateLimit, pageLinks{}, err
 	}
 	defer resp.Body.Close()
 
-	rateLimit := parseRateLimit(resp)
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		// 304 には Link ヘッダーが含まれない場合があるため、キャッシュ時のものを使う
+		return rateLimit, parseLinkHeader(cached.Link), decodeBody(cached.Body, result)
+	}
 
 	if resp.StatusCode != http.StatusOK {
-		return rateLimit, &GitHubError{
+		return rateLimit, pageLinks{}, &GitHubError{
 			StatusCode: resp.StatusCode,
 			Message:    getErrorMessage(resp.StatusCode),
 		}
 	}
 
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return rateLimit, err
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return rateLimit, pageLinks{}, err
+	}
+
+	link := resp.Header.Get("Link")
+	if c.cache != nil {
+		etag := resp.Header.Get("ETag")
+		lastModified := resp.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			_ = c.cache.Set(ctx, key, &CachedResponse{
+				ETag:         etag,
+				LastModified: lastModified,
+				Link:         link,
+				Body:         body,
+			})
 		}
 	}
 
-	return rateLimit, nil
+	return rateLimit, parseLinkHeader(link), decodeBody(body, result)
 }
 
-func (c *Client) GetAssignedIssues(page, perPage int) ([]Issue, *RateLimitInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/issues?page=%d&per_page=%d&state=open", page, perPage)
+// decodeBody はレスポンスボディを result にデコードします（result が nil の場合は何もしません）
+func decodeBody(body []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(body, result)
+}
 
-	var issues []Issue
-	rateLimit, err := c.doRequest(url, &issues)
+func (c *Client) GetAssignedIssues(ctx context.Context, filter IssueFilter, page, perPage int) ([]Issue, *RateLimitInfo, error) {
+	issues, rateLimit, _, err := fetchPage[Issue](ctx, c, c.assignedIssuesURL(filter, page, perPage))
 	return issues, rateLimit, err
 }
 
-func (c *Client) GetRepositoryIssues(owner, repo string, page, perPage int) ([]Issue, *RateLimitInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues?page=%d&per_page=%d&state=open", owner, repo, page, perPage)
+func (c *Client) assignedIssuesURL(filter IssueFilter, page, perPage int) string {
+	return c.endpoint("/issues?%s", filter.assignedQuery(page, perPage))
+}
 
-	var issues []Issue
-	rateLimit, err := c.doRequest(url, &issues)
+func (c *Client) GetRepositoryIssues(ctx context.Context, owner, repo string, filter IssueFilter, page, perPage int) ([]Issue, *RateLimitInfo, error) {
+	issues, rateLimit, _, err := fetchPage[Issue](ctx, c, c.repositoryIssuesURL(owner, repo, filter, page, perPage))
 	return issues, rateLimit, err
 }
 
-func (c *Client) ValidateToken() error {
-	_, err := c.doRequest("https://api.github.com/user", nil)
-	return err
+func (c *Client) repositoryIssuesURL(owner, repo string, filter IssueFilter, page, perPage int) string {
+	return c.endpoint("/repos/%s/%s/issues?%s", owner, repo, filter.repositoryQuery(page, perPage))
 }
 
-func (c *Client) GetAllAssignedIssues() ([]Issue, *RateLimitInfo, error) {
-	return collectAllPages(func(page int) ([]Issue, *RateLimitInfo, error) {
-		return c.GetAssignedIssues(page, maxPerPage)
+// GetAllAssignedIssues は自分に割り当てられた Issue をすべてのページから取得します。
+// /issues が対応していない条件（担当者・作成者など）は含めないため、呼び出し側で filter.Matches を適用してください。
+func (c *Client) GetAllAssignedIssues(ctx context.Context, filter IssueFilter) ([]Issue, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.assignedIssuesURL(filter, 1, maxPerPage), func(page int) ([]Issue, *RateLimitInfo, pageLinks, error) {
+		return fetchPage[Issue](ctx, c, c.assignedIssuesURL(filter, page, maxPerPage))
 	})
 }
 
-func (c *Client) GetAllRepositoryIssues(owner, repo string) ([]Issue, *RateLimitInfo, error) {
-	return collectAllPages(func(page int) ([]Issue, *RateLimitInfo, error) {
-		return c.GetRepositoryIssues(owner, repo, page, maxPerPage)
+// GetAllRepositoryIssues はリポジトリの Issue をすべてのページから取得します。
+// 除外ラベルとタイトルでのマイルストーン指定は API で指定できないため、呼び出し側で filter.Matches を適用してください。
+func (c *Client) GetAllRepositoryIssues(ctx context.Context, owner, repo string, filter IssueFilter) ([]Issue, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.repositoryIssuesURL(owner, repo, filter, 1, maxPerPage), func(page int) ([]Issue, *RateLimitInfo, pageLinks, error) {
+		return fetchPage[Issue](ctx, c, c.repositoryIssuesURL(owner, repo, filter, page, maxPerPage))
 	})
 }
 
-func (c *Client) GetUserRepositories(page, perPage int) ([]Repository, *RateLimitInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/user/repos?page=%d&per_page=%d&affiliation=owner,collaborator,organization_member", page, perPage)
+func (c *Client) GetUserRepositories(ctx context.Context, page, perPage int) ([]Repository, *RateLimitInfo, error) {
+	repos, rateLimit, _, err := fetchPage[Repository](ctx, c, c.userRepositoriesURL(page, perPage))
+	return repos, rateLimit, err
+}
+
+func (c *Client) userRepositoriesURL(page, perPage int) string {
+	return c.endpoint("/user/repos?page=%d&per_page=%d&affiliation=owner,collaborator,organization_member", page, perPage)
+}
 
-	var repos []Repository
-	rateLimit, err := c.doRequest(url, &repos)
+func (c *Client) GetAllUserRepositories(ctx context.Context) ([]Repository, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.userRepositoriesURL(1, maxPerPage), func(page int) ([]Repository, *RateLimitInfo, pageLinks, error) {
+		return fetchPage[Repository](ctx, c, c.userRepositoriesURL(page, maxPerPage))
+	})
+}
+
+// GetSpecificUserRepositories gets all repositories for a specific user
+func (c *Client) GetSpecificUserRepositories(ctx context.Context, username string, page, perPage int) ([]Repository, *RateLimitInfo, error) {
+	repos, rateLimit, _, err := fetchPage[Repository](ctx, c, c.specificUserRepositoriesURL(username, page, perPage))
 	return repos, rateLimit, err
 }
 
-// collectAllPages は汎用的なページネーション処理を行います
-func collectAllPages[T any](fetch func(page int) ([]T, *RateLimitInfo, error)) ([]T, *RateLimitInfo, error) {
-	var allItems []T
-	var lastRateLimit *RateLimitInfo
+func (c *Client) specificUserRepositoriesURL(username string, page, perPage int) string {
+	return c.endpoint("/users/%s/repos?page=%d&per_page=%d&type=all", username, page, perPage)
+}
 
-	for page := 1; ; page++ {
-		items, rateLimit, err := fetch(page)
-		if err != nil {
-			return nil, rateLimit, err
-		}
+// GetAllSpecificUserRepositories gets all repositories for a specific user (all pages)
+func (c *Client) GetAllSpecificUserRepositories(ctx context.Context, username string) ([]Repository, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.specificUserRepositoriesURL(username, 1, maxPerPage), func(page int) ([]Repository, *RateLimitInfo, pageLinks, error) {
+		return fetchPage[Repository](ctx, c, c.specificUserRepositoriesURL(username, page, maxPerPage))
+	})
+}
 
-		if rateLimit != nil {
-			lastRateLimit = rateLimit
-		}
+// SearchIssuesResult は Search API (/search/issues) のレスポンスです
+type SearchIssuesResult struct {
+	TotalCount        int     `json:"total_count"`
+	IncompleteResults bool    `json:"incomplete_results"`
+	Items             []Issue `json:"items"`
+}
 
-		allItems = append(allItems, items...)
+// MaxSearchResults は Search API で取得できる最大件数です
+const MaxSearchResults = 1000
 
-		if len(items) < maxPerPage {
-			break
-		}
-	}
+// ErrSearchResultLimitExceeded は検索結果が Search API の取得上限を超えた場合に返されます
+var ErrSearchResultLimitExceeded = errors.New("search results exceed the search API limit")
 
-	return allItems, lastRateLimit, nil
+// SearchIssues は Search API で Issue を検索します。
+// 検索結果の Issue には repository_url からリポジトリ情報を補完します。
+func (c *Client) SearchIssues(ctx context.Context, query string, page, perPage int) (*SearchIssuesResult, *RateLimitInfo, error) {
+	result, rateLimit, _, err := c.searchIssuesPage(ctx, query, page, perPage)
+	return result, rateLimit, err
 }
 
-func (c *Client) GetAllUserRepositories() ([]Repository, *RateLimitInfo, error) {
-	return collectAllPages(func(page int) ([]Repository, *RateLimitInfo, error) {
-		return c.GetUserRepositories(page, maxPerPage)
-	})
+func (c *Client) searchIssuesURL(query string, page, perPage int) string {
+	return c.endpoint("/search/issues?q=%s&page=%d&per_page=%d", url.QueryEscape(query), page, perPage)
 }
 
-// GetSpecificUserRepositories gets all repositories for a specific user
-func (c *Client) GetSpecificUserRepositories(username string, page, perPage int) ([]Repository, *RateLimitInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s/repos?page=%d&per_page=%d&type=all", username, page, perPage)
+func (c *Client) searchIssuesPage(ctx context.Context, query string, page, perPage int) (*SearchIssuesResult, *RateLimitInfo, pageLinks, error) {
+	var result SearchIssuesResult
+	rateLimit, links, err := c.doPageRequest(ctx, c.searchIssuesURL(query, page, perPage), &result)
+	if err != nil {
+		return nil, rateLimit, links, err
+	}
 
-	var repos []Repository
-	rateLimit, err := c.doRequest(url, &repos)
-	return repos, rateLimit, err
+	for idx := range result.Items {
+		if result.Items[idx].Repository == nil {
+			if fullName := repositoryFullNameFromURL(result.Items[idx].RepositoryURL); fullName != "" {
+				result.Items[idx].Repository = &Repository{FullName: fullName}
+			}
+		}
+	}
+	return &result, rateLimit, links, nil
 }
 
-// GetAllSpecificUserRepositories gets all repositories for a specific user (all pages)
-func (c *Client) GetAllSpecificUserRepositories(username string) ([]Repository, *RateLimitInfo, error) {
-	return collectAllPages(func(page int) ([]Repository, *RateLimitInfo, error) {
-		return c.GetSpecificUserRepositories(username, page, maxPerPage)
+// SearchAllIssues は検索結果をすべてのページから取得します。
+// 総件数が MaxSearchResults を超える場合は、取得を行わず ErrSearchResultLimitExceeded を返します。
+func (c *Client) SearchAllIssues(ctx context.Context, query string) ([]Issue, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.searchIssuesURL(query, 1, maxPerPage), func(page int) ([]Issue, *RateLimitInfo, pageLinks, error) {
+		result, rateLimit, links, err := c.searchIssuesPage(ctx, query, page, maxPerPage)
+		if err != nil {
+			return nil, rateLimit, links, err
+		}
+		if page == 1 && result.TotalCount > MaxSearchResults {
+			return nil, rateLimit, links, ErrSearchResultLimitExceeded
+		}
+		return result.Items, rateLimit, links, nil
 	})
 }
 
+// repositoryFullNameFromURL は "https://api.github.com/repos/owner/repo" 形式のURLから "owner/repo" を取り出します
+func repositoryFullNameFromURL(repositoryURL string) string {
+	idx := strings.LastIndex(repositoryURL, "/repos/")
+	if idx < 0 {
+		return ""
+	}
+	fullName := repositoryURL[idx+len("/repos/"):]
+	if len(strings.Split(fullName, "/")) != 2 {
+		return ""
+	}
+	return fullName
+}
 
 func parseRateLimit(resp *http.Response) *RateLimitInfo {
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
 	remaining, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Used"))
 	resetUnix, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
 
 	return &RateLimitInfo{
+		Limit:     limit,
 		Remaining: remaining,
+		Used:      used,
+		Resource:  resp.Header.Get("X-RateLimit-Resource"),
 		ResetAt:   time.Unix(resetUnix, 0),
 	}
 }
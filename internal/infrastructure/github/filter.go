@@ -0,0 +1,258 @@
+package github
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IssueState は取得する Issue の状態です
+type IssueState string
+
+const (
+	IssueStateOpen   IssueState = "open"
+	IssueStateClosed IssueState = "closed"
+	IssueStateAll    IssueState = "all"
+)
+
+// IssueSort は Issue の並び順のキーです
+type IssueSort string
+
+const (
+	IssueSortCreated  IssueSort = "created"
+	IssueSortUpdated  IssueSort = "updated"
+	IssueSortComments IssueSort = "comments"
+	IssueSortAge      IssueSort = "age" // 作成からの経過時間（desc は古い順）
+)
+
+// 並び順の方向
+const (
+	SortDirectionAsc  = "asc"
+	SortDirectionDesc = "desc"
+)
+
+// マイルストーン・担当者の絞り込みで使う特別な値
+const (
+	FilterAny  = "*"    // いずれかが設定されている
+	FilterNone = "none" // 設定されていない
+)
+
+// IssueFilter は Issue の絞り込み条件と並び順です。
+// 各 API で指定できる条件はリクエストのパラメータに変換し、指定できない条件は Matches で取得後に適用します。
+type IssueFilter struct {
+	State         IssueState // 空の場合は open
+	Labels        []string   // すべてのラベルを持つ Issue
+	ExcludeLabels []string   // いずれのラベルも持たない Issue
+	Milestone     string     // マイルストーンのタイトルまたは番号、FilterAny、FilterNone
+	Assignee      string     // 担当者の login、FilterAny、FilterNone
+	Creator       string     // 作成者の login
+	Mentioned     string     // メンションされたユーザーの login
+	Since         time.Time  // この日時以降に更新された Issue
+	Sort          IssueSort  // 空の場合は API の既定順
+	Direction     string     // SortDirectionAsc / SortDirectionDesc（空の場合は desc）
+}
+
+// state は取得する状態を返します。未指定の場合は open です。
+func (f IssueFilter) state() IssueState {
+	if f.State == "" {
+		return IssueStateOpen
+	}
+	return f.State
+}
+
+// Sorted は並び順が指定されているかを返します
+func (f IssueFilter) Sorted() bool {
+	return f.Sort != "" || f.Direction != ""
+}
+
+// sortKey は API に指定する並び順のキーを返します。age は作成日時で並べます。
+func (f IssueFilter) sortKey() IssueSort {
+	if f.Sort == "" || f.Sort == IssueSortAge {
+		return IssueSortCreated
+	}
+	return f.Sort
+}
+
+// direction は API に指定する並び順の方向を返します。age は作成日時と逆の方向になります。
+func (f IssueFilter) direction() string {
+	ascending := f.Direction == SortDirectionAsc
+	if f.Sort == IssueSortAge {
+		ascending = !ascending
+	}
+	if ascending {
+		return SortDirectionAsc
+	}
+	return SortDirectionDesc
+}
+
+// milestoneParam は REST API の milestone パラメータに指定できる値（番号・"*"・"none"）を返します。
+// タイトルは指定できないため空を返します。
+func (f IssueFilter) milestoneParam() string {
+	if f.Milestone == FilterAny || f.Milestone == FilterNone {
+		return f.Milestone
+	}
+	if _, err := strconv.Atoi(f.Milestone); err == nil {
+		return f.Milestone
+	}
+	return ""
+}
+
+// commonQuery は /issues と /repos/{owner}/{repo}/issues に共通するパラメータを返します
+func (f IssueFilter) commonQuery(page, perPage int) url.Values {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	query.Set("state", string(f.state()))
+	if len(f.Labels) > 0 {
+		query.Set("labels", strings.Join(f.Labels, ","))
+	}
+	if !f.Since.IsZero() {
+		query.Set("since", f.Since.UTC().Format(time.RFC3339))
+	}
+	if f.Sorted() {
+		query.Set("sort", string(f.sortKey()))
+		query.Set("direction", f.direction())
+	}
+	return query
+}
+
+// repositoryQuery は /repos/{owner}/{repo}/issues のクエリ文字列を返します
+func (f IssueFilter) repositoryQuery(page, perPage int) string {
+	query := f.commonQuery(page, perPage)
+	if milestone := f.milestoneParam(); milestone != "" {
+		query.Set("milestone", milestone)
+	}
+	if f.Assignee != "" {
+		query.Set("assignee", f.Assignee)
+	}
+	if f.Creator != "" {
+		query.Set("creator", f.Creator)
+	}
+	if f.Mentioned != "" {
+		query.Set("mentioned", f.Mentioned)
+	}
+	return query.Encode()
+}
+
+// assignedQuery は /issues（自分に割り当てられた Issue）のクエリ文字列を返します
+func (f IssueFilter) assignedQuery(page, perPage int) string {
+	return f.commonQuery(page, perPage).Encode()
+}
+
+// SearchQualifiers は Search API の検索修飾子を返します
+func (f IssueFilter) SearchQualifiers() []string {
+	var qualifiers []string
+	switch f.state() {
+	case IssueStateOpen:
+		qualifiers = append(qualifiers, "is:open")
+	case IssueStateClosed:
+		qualifiers = append(qualifiers, "is:closed")
+	}
+	for _, label := range f.Labels {
+		qualifiers = append(qualifiers, "label:"+quoteSearchValue(label))
+	}
+	for _, label := range f.ExcludeLabels {
+		qualifiers = append(qualifiers, "-label:"+quoteSearchValue(label))
+	}
+	switch {
+	case f.Milestone == FilterNone:
+		qualifiers = append(qualifiers, "no:milestone")
+	case f.Milestone != "" && f.Milestone != FilterAny && f.milestoneParam() == "":
+		// 検索ではタイトルで指定する（番号・"*" は取得後に絞り込む）
+		qualifiers = append(qualifiers, "milestone:"+quoteSearchValue(f.Milestone))
+	}
+	switch {
+	case f.Assignee == FilterNone:
+		qualifiers = append(qualifiers, "no:assignee")
+	case f.Assignee != "" && f.Assignee != FilterAny:
+		qualifiers = append(qualifiers, "assignee:"+f.Assignee)
+	}
+	if f.Creator != "" {
+		qualifiers = append(qualifiers, "author:"+f.Creator)
+	}
+	if f.Mentioned != "" {
+		qualifiers = append(qualifiers, "mentions:"+f.Mentioned)
+	}
+	if !f.Since.IsZero() {
+		qualifiers = append(qualifiers, "updated:>="+f.Since.UTC().Format(time.RFC3339))
+	}
+	return qualifiers
+}
+
+// quoteSearchValue は空白を含む値を引用符で囲みます
+func quoteSearchValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	return value
+}
+
+// Matches は Issue が絞り込み条件に該当するかを返します。
+// API で指定できない条件を取得後に適用するためのもので、メンションは判定できないため考慮しません。
+func (f IssueFilter) Matches(issue Issue) bool {
+	switch f.state() {
+	case IssueStateOpen:
+		if issue.State != "" && issue.State != string(IssueStateOpen) {
+			return false
+		}
+	case IssueStateClosed:
+		if issue.State == string(IssueStateOpen) {
+			return false
+		}
+	}
+	for _, label := range f.Labels {
+		if !issue.HasLabel(label) {
+			return false
+		}
+	}
+	for _, label := range f.ExcludeLabels {
+		if issue.HasLabel(label) {
+			return false
+		}
+	}
+	if f.Milestone != "" && !f.matchesMilestone(issue.Milestone) {
+		return false
+	}
+	if f.Assignee != "" && !f.matchesAssignee(issue.Assignees) {
+		return false
+	}
+	if f.Creator != "" && (issue.User == nil || !strings.EqualFold(issue.User.Login, f.Creator)) {
+		return false
+	}
+	if !f.Since.IsZero() && issue.UpdatedAt.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+func (f IssueFilter) matchesMilestone(milestone *Milestone) bool {
+	switch f.Milestone {
+	case FilterAny:
+		return milestone != nil
+	case FilterNone:
+		return milestone == nil
+	}
+	if milestone == nil {
+		return false
+	}
+	if number, err := strconv.Atoi(f.Milestone); err == nil {
+		return milestone.Number == number
+	}
+	return strings.EqualFold(milestone.Title, f.Milestone)
+}
+
+func (f IssueFilter) matchesAssignee(assignees []User) bool {
+	switch f.Assignee {
+	case FilterAny:
+		return len(assignees) > 0
+	case FilterNone:
+		return len(assignees) == 0
+	}
+	for _, assignee := range assignees {
+		if strings.EqualFold(assignee.Login, f.Assignee) {
+			return true
+		}
+	}
+	return false
+}
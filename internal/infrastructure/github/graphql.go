@@ -0,0 +1,485 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GraphQLClient は GitHub GraphQL API (v4) のクライアントです。
+// REST の Client と同じ Issue 型を返すため、呼び出し側はバックエンドを意識せずに切り替えられます。
+type GraphQLClient struct {
+	httpClient *http.Client
+	token      string
+	endpoint   string
+	clientOptions
+}
+
+// graphQLRepositoriesPerQuery は1回のクエリでまとめて取得するリポジトリ数です
+const graphQLRepositoriesPerQuery = 10
+
+// GraphQLIssueFields は REST では追加リクエストが必要な関連情報のうち、取得するものを指定します
+type GraphQLIssueFields struct {
+	Milestone          bool // マイルストーン
+	ProjectStatus      bool // Projects (v2) の Status フィールド
+	LinkedPullRequests bool // Issue をクローズする紐付き Pull Request
+}
+
+// AllGraphQLIssueFields はすべての関連情報を取得します
+var AllGraphQLIssueFields = GraphQLIssueFields{
+	Milestone:          true,
+	ProjectStatus:      true,
+	LinkedPullRequests: true,
+}
+
+// GraphQLError は GraphQL API がエラーを返した場合のエラーです
+type GraphQLError struct {
+	Messages []string
+}
+
+func (e *GraphQLError) Error() string {
+	return fmt.Sprintf("GitHub GraphQL error: %s", strings.Join(e.Messages, "; "))
+}
+
+// RepositoryIssues は1リポジトリ分の Issue 取得結果です
+type RepositoryIssues struct {
+	FullName string
+	Issues   []Issue
+	Err      error
+}
+
+// NewGraphQLClient は GraphQL クライアントを生成します。
+// baseURL には REST API のベースURLを指定し、空の場合は github.com を利用します。
+// opts には REST の Client と同じ生成オプションを指定できます。
+func NewGraphQLClient(token, baseURL string, opts ...Option) *GraphQLClient {
+	return &GraphQLClient{
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		token:         token,
+		endpoint:      graphQLEndpoint(baseURL),
+		clientOptions: newClientOptions(opts),
+	}
+}
+
+// graphQLEndpoint は REST API のベースURLから GraphQL のエンドポイントを求めます。
+// github.com は "https://api.github.com/graphql"、GitHub Enterprise Server は "https://host/api/graphql" です。
+func graphQLEndpoint(baseURL string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" || baseURL == DefaultBaseURL {
+		return DefaultBaseURL + "/graphql"
+	}
+	if strings.HasSuffix(baseURL, "/api/v3") {
+		return strings.TrimSuffix(baseURL, "/v3") + "/graphql"
+	}
+	return baseURL + "/graphql"
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path"`
+	} `json:"errors"`
+}
+
+// do は GraphQL クエリを実行し、data を result にデコードします。
+// 一部のフィールドのみが失敗した場合は、エラーをパスの先頭要素（エイリアス）ごとに返します。
+func (c *GraphQLClient) do(ctx context.Context, query string, variables map[string]interface{}, result interface{}) (*RateLimitInfo, map[string]error, error) {
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, rateLimit, err := sendWithRetry(ctx, c.httpClient, c.retry, c.rateLimits, RateLimitResourceGraphQL, true, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return rateLimit, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return rateLimit, nil, &GitHubError{
+			StatusCode: resp.StatusCode,
+			Message:    getErrorMessage(resp.StatusCode),
+		}
+	}
+
+	var gqlResp graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gqlResp); err != nil {
+		return rateLimit, nil, err
+	}
+
+	fieldErrors := make(map[string]error)
+	var globalMessages []string
+	for _, gqlErr := range gqlResp.Errors {
+		if len(gqlErr.Path) > 0 {
+			if alias, ok := gqlErr.Path[0].(string); ok {
+				fieldErrors[alias] = &GraphQLError{Messages: []string{gqlErr.Message}}
+				continue
+			}
+		}
+		globalMessages = append(globalMessages, gqlErr.Message)
+	}
+	if len(globalMessages) > 0 || len(gqlResp.Data) == 0 || string(gqlResp.Data) == "null" {
+		if len(globalMessages) == 0 {
+			globalMessages = []string{"empty response"}
+		}
+		return rateLimit, nil, &GraphQLError{Messages: globalMessages}
+	}
+
+	if err := json.Unmarshal(gqlResp.Data, result); err != nil {
+		return rateLimit, nil, err
+	}
+	return rateLimit, fieldErrors, nil
+}
+
+// graphQLIssueNode は GraphQL の Issue ノードです
+type graphQLIssueNode struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Author    *User     `json:"author"`
+	Comments  struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	Labels struct {
+		Nodes []Label `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []User `json:"nodes"`
+	} `json:"assignees"`
+	Milestone *struct {
+		Number int        `json:"number"`
+		Title  string     `json:"title"`
+		DueOn  *time.Time `json:"dueOn"`
+	} `json:"milestone"`
+	ProjectItems struct {
+		Nodes []struct {
+			FieldValueByName *struct {
+				Name string `json:"name"`
+			} `json:"fieldValueByName"`
+		} `json:"nodes"`
+	} `json:"projectItems"`
+	ClosedByPullRequestsReferences struct {
+		Nodes []LinkedPullRequest `json:"nodes"`
+	} `json:"closedByPullRequestsReferences"`
+}
+
+// toIssue は GraphQL のノードを REST と同じ Issue 型に変換します
+func (n graphQLIssueNode) toIssue(fullName string) Issue {
+	issue := Issue{
+		Number:             n.Number,
+		Title:              n.Title,
+		HTMLURL:            n.URL,
+		State:              strings.ToLower(n.State),
+		CreatedAt:          n.CreatedAt,
+		UpdatedAt:          n.UpdatedAt,
+		Comments:           n.Comments.TotalCount,
+		User:               n.Author,
+		Labels:             n.Labels.Nodes,
+		Assignees:          n.Assignees.Nodes,
+		Repository:         &Repository{FullName: fullName},
+		LinkedPullRequests: n.ClosedByPullRequestsReferences.Nodes,
+	}
+	if n.Milestone != nil {
+		issue.Milestone = &Milestone{Number: n.Milestone.Number, Title: n.Milestone.Title, DueOn: n.Milestone.DueOn}
+	}
+	for _, item := range n.ProjectItems.Nodes {
+		if item.FieldValueByName != nil && item.FieldValueByName.Name != "" {
+			issue.ProjectStatus = item.FieldValueByName.Name
+			break
+		}
+	}
+	return issue
+}
+
+type graphQLIssueConnection struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []*graphQLIssueNode `json:"nodes"` // 閲覧できないノードは null になる
+}
+
+type graphQLRepositoryNode struct {
+	Issues       *graphQLIssueConnection `json:"issues"`
+	PullRequests *graphQLIssueConnection `json:"pullRequests"`
+}
+
+// GraphQL のリポジトリ配下のコネクション名
+const (
+	graphQLConnectionIssues       = "issues"
+	graphQLConnectionPullRequests = "pullRequests"
+)
+
+// issueFieldsFragment は取得するフィールドに応じた Issue / Pull Request のフラグメントを組み立てます
+func issueFieldsFragment(connection string, fields GraphQLIssueFields) string {
+	var b strings.Builder
+	if connection == graphQLConnectionPullRequests {
+		b.WriteString("fragment PullRequestFields on PullRequest { number title url state createdAt updatedAt ")
+	} else {
+		b.WriteString("fragment IssueFields on Issue { number title url state createdAt updatedAt ")
+	}
+	b.WriteString("author { login } comments { totalCount } ")
+	b.WriteString("labels(first: 20) { nodes { name color } } ")
+	b.WriteString("assignees(first: 10) { nodes { login } } ")
+	if fields.Milestone {
+		b.WriteString("milestone { number title dueOn } ")
+	}
+	if fields.ProjectStatus {
+		b.WriteString(`projectItems(first: 5) { nodes { fieldValueByName(name: "Status") { ... on ProjectV2ItemFieldSingleSelectValue { name } } } } `)
+	}
+	if fields.LinkedPullRequests && connection == graphQLConnectionIssues {
+		b.WriteString("closedByPullRequestsReferences(first: 5, includeClosedPrs: true) { nodes { number url state } } ")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// repositoryCursor は取得途中のリポジトリ・コネクションとページのカーソルです
+type repositoryCursor struct {
+	index      int
+	owner      string
+	name       string
+	connection string
+	cursor     string
+}
+
+// graphQLOrderFields は IssueSort に対応する GraphQL の並び順のフィールドです
+var graphQLOrderFields = map[IssueSort]string{
+	IssueSortCreated:  "CREATED_AT",
+	IssueSortUpdated:  "UPDATED_AT",
+	IssueSortComments: "COMMENTS",
+}
+
+// graphQLStates はコネクションの states 引数を返します（すべての状態を対象にする場合は空）
+func graphQLStates(connection string, filter IssueFilter) string {
+	switch filter.state() {
+	case IssueStateOpen:
+		return "OPEN"
+	case IssueStateClosed:
+		if connection == graphQLConnectionPullRequests {
+			return "[CLOSED, MERGED]"
+		}
+		return "CLOSED"
+	default:
+		return ""
+	}
+}
+
+// graphQLOrderBy はコネクションの orderBy 引数を返します（未指定の場合は更新日時の降順）
+func graphQLOrderBy(filter IssueFilter) string {
+	if !filter.Sorted() {
+		return "{field: UPDATED_AT, direction: DESC}"
+	}
+	return fmt.Sprintf("{field: %s, direction: %s}", graphQLOrderFields[filter.sortKey()], strings.ToUpper(filter.direction()))
+}
+
+// graphQLIssueFilterBy は Issue コネクションの filterBy に指定する IssueFilters を返します（条件がない場合は nil）。
+// タイトルでのマイルストーン指定・担当者なし・除外ラベルは指定できないため、取得後に Matches で絞り込みます。
+func graphQLIssueFilterBy(filter IssueFilter) map[string]interface{} {
+	filterBy := make(map[string]interface{})
+	if len(filter.Labels) > 0 {
+		filterBy["labels"] = filter.Labels
+	}
+	if milestone := filter.milestoneParam(); milestone != "" {
+		filterBy["milestoneNumber"] = milestone
+	}
+	if filter.Assignee != "" && filter.Assignee != FilterNone {
+		filterBy["assignee"] = filter.Assignee
+	}
+	if filter.Creator != "" {
+		filterBy["createdBy"] = filter.Creator
+	}
+	if filter.Mentioned != "" {
+		filterBy["mentioned"] = filter.Mentioned
+	}
+	if !filter.Since.IsZero() {
+		filterBy["since"] = filter.Since.UTC().Format(time.RFC3339)
+	}
+	if len(filterBy) == 0 {
+		return nil
+	}
+	return filterBy
+}
+
+// buildRepositoriesIssuesQuery は複数リポジトリの Issue / Pull Request をエイリアスでまとめて取得するクエリを組み立てます。
+// Pull Request のコネクションは状態とラベルのみ絞り込めるため、それ以外の条件は取得後に Matches で絞り込みます。
+func buildRepositoriesIssuesQuery(targets []repositoryCursor, fields GraphQLIssueFields, filter IssueFilter) (string, map[string]interface{}) {
+	var params, selections []string
+	variables := make(map[string]interface{})
+	fragments := make(map[string]string)
+	filterBy := graphQLIssueFilterBy(filter)
+	orderBy := graphQLOrderBy(filter)
+
+	for idx, target := range targets {
+		fragmentName := "IssueFields"
+		if target.connection == graphQLConnectionPullRequests {
+			fragmentName = "PullRequestFields"
+		}
+		// 未使用のフラグメントはエラーになるため、使うものだけを含める
+		fragments[target.connection] = issueFieldsFragment(target.connection, fields)
+
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!, $c%d: String", idx, idx, idx))
+		args := []string{fmt.Sprintf("first: %d, after: $c%d", maxPerPage, idx)}
+		if states := graphQLStates(target.connection, filter); states != "" {
+			args = append(args, "states: "+states)
+		}
+		args = append(args, "orderBy: "+orderBy)
+		switch {
+		case target.connection == graphQLConnectionIssues && filterBy != nil:
+			args = append(args, "filterBy: $filterBy")
+			variables["filterBy"] = filterBy
+		case target.connection == graphQLConnectionPullRequests && len(filter.Labels) > 0:
+			args = append(args, "labels: $labels")
+			variables["labels"] = filter.Labels
+		}
+		selections = append(selections, fmt.Sprintf(
+			"r%d: repository(owner: $o%d, name: $n%d) { %s(%s) { pageInfo { hasNextPage endCursor } nodes { ...%s } } }",
+			idx, idx, idx, target.connection, strings.Join(args, ", "), fragmentName))
+
+		variables[fmt.Sprintf("o%d", idx)] = target.owner
+		variables[fmt.Sprintf("n%d", idx)] = target.name
+		if target.cursor != "" {
+			variables[fmt.Sprintf("c%d", idx)] = target.cursor
+		}
+	}
+
+	// 未使用の変数はエラーになるため、使うものだけを宣言する
+	if _, ok := variables["filterBy"]; ok {
+		params = append(params, "$filterBy: IssueFilters")
+	}
+	if _, ok := variables["labels"]; ok {
+		params = append(params, "$labels: [String!]")
+	}
+
+	var fragmentDefs []string
+	for _, connection := range []string{graphQLConnectionIssues, graphQLConnectionPullRequests} {
+		if fragment, ok := fragments[connection]; ok {
+			fragmentDefs = append(fragmentDefs, fragment)
+		}
+	}
+
+	query := fmt.Sprintf("query(%s) { %s }\n%s",
+		strings.Join(params, ", "),
+		strings.Join(selections, " "),
+		strings.Join(fragmentDefs, "\n"))
+	return query, variables
+}
+
+// GetRepositoriesIssues は複数リポジトリの Issue / Pull Request を、リポジトリをまとめたクエリで取得します。
+// itemType で Issue・Pull Request のどちらを取得するかを、filter で状態・絞り込み条件・並び順を指定します。
+// 結果は repos と同じ順序で返し、リポジトリ単位の失敗は RepositoryIssues.Err に設定します。
+// ctx が終了した場合は、それまでの結果と ctx のエラーを返します。
+func (c *GraphQLClient) GetRepositoriesIssues(ctx context.Context, repos []string, itemType ItemType, fields GraphQLIssueFields, filter IssueFilter) ([]RepositoryIssues, *RateLimitInfo, error) {
+	var connections []string
+	if itemType.IncludesIssues() {
+		connections = append(connections, graphQLConnectionIssues)
+	}
+	if itemType.IncludesPullRequests() {
+		connections = append(connections, graphQLConnectionPullRequests)
+	}
+
+	results := make([]RepositoryIssues, len(repos))
+	var pending []repositoryCursor
+	for idx, fullName := range repos {
+		results[idx].FullName = fullName
+		parts := strings.SplitN(fullName, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			results[idx].Err = fmt.Errorf("invalid repository name: %s", fullName)
+			continue
+		}
+		for _, connection := range connections {
+			pending = append(pending, repositoryCursor{index: idx, owner: parts[0], name: parts[1], connection: connection})
+		}
+	}
+
+	var lastRateLimit *RateLimitInfo
+	for len(pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return results, lastRateLimit, err
+		}
+
+		batch := pending
+		if len(batch) > graphQLRepositoriesPerQuery {
+			batch = batch[:graphQLRepositoriesPerQuery]
+		}
+		pending = pending[len(batch):]
+
+		query, variables := buildRepositoriesIssuesQuery(batch, fields, filter)
+		var data map[string]*graphQLRepositoryNode
+		rateLimit, fieldErrors, err := c.do(ctx, query, variables, &data)
+		if rateLimit != nil {
+			lastRateLimit = rateLimit
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return results, lastRateLimit, ctx.Err()
+			}
+			for _, target := range batch {
+				results[target.index].Err = err
+			}
+			continue
+		}
+
+		for idx, target := range batch {
+			alias := fmt.Sprintf("r%d", idx)
+			node := data[alias]
+			if node == nil {
+				if fieldErr, ok := fieldErrors[alias]; ok {
+					results[target.index].Err = fieldErr
+				} else {
+					results[target.index].Err = &GitHubError{StatusCode: http.StatusNotFound, Message: getErrorMessage(http.StatusNotFound)}
+				}
+				continue
+			}
+
+			conn := node.Issues
+			if target.connection == graphQLConnectionPullRequests {
+				conn = node.PullRequests
+			}
+			if conn == nil {
+				continue
+			}
+
+			fullName := repos[target.index]
+			for _, issueNode := range conn.Nodes {
+				if issueNode == nil {
+					continue
+				}
+				issue := issueNode.toIssue(fullName)
+				if target.connection == graphQLConnectionPullRequests {
+					issue.PullRequest = &PullRequestRef{HTMLURL: issueNode.URL}
+				}
+				results[target.index].Issues = append(results[target.index].Issues, issue)
+			}
+
+			// 次のページがあるリポジトリは次のバッチで続きを取得する
+			if conn.PageInfo.HasNextPage {
+				target.cursor = conn.PageInfo.EndCursor
+				pending = append(pending, target)
+			}
+		}
+	}
+
+	return results, lastRateLimit, nil
+}
@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// IssueTemplateDir は Issue テンプレートを置くリポジトリ内のディレクトリです
+const IssueTemplateDir = ".github/ISSUE_TEMPLATE"
+
+// IssueTemplate は Markdown 形式の Issue テンプレートです。
+// YAML 形式の Issue フォーム (*.yml) は Discord のモーダルで再現できないため対象外です。
+type IssueTemplate struct {
+	FileName string   // テンプレートのファイル名 (bug_report.md など)
+	Name     string   // front matter の name (未指定の場合はファイル名)
+	About    string   // front matter の about
+	Title    string   // front matter の title (Issue のタイトルの初期値)
+	Labels   []string // front matter の labels
+	Body     string   // front matter を除いた本文
+}
+
+// contentEntry は Contents API のファイル・ディレクトリです
+type contentEntry struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// isMarkdownTemplate は Markdown 形式のテンプレートのファイル名かどうかを返します
+func isMarkdownTemplate(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".md")
+}
+
+// GetIssueTemplateNames はリポジトリの .github/ISSUE_TEMPLATE にある Markdown 形式のテンプレートのファイル名を返します。
+// ディレクトリがない場合は空のスライスを返します。
+func (c *Client) GetIssueTemplateNames(ctx context.Context, owner, repo string) ([]string, *RateLimitInfo, error) {
+	var entries []contentEntry
+	rateLimit, err := c.doRequest(ctx, c.endpoint("/repos/%s/%s/contents/%s", url.PathEscape(owner), url.PathEscape(repo), IssueTemplateDir), &entries)
+	if err != nil {
+		var ghErr *GitHubError
+		if errors.As(err, &ghErr) && ghErr.StatusCode == http.StatusNotFound {
+			return []string{}, rateLimit, nil
+		}
+		return nil, rateLimit, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.Type == "file" && isMarkdownTemplate(entry.Name) {
+			names = append(names, entry.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, rateLimit, nil
+}
+
+// GetIssueTemplate は .github/ISSUE_TEMPLATE にある Markdown 形式のテンプレートを取得します
+func (c *Client) GetIssueTemplate(ctx context.Context, owner, repo, fileName string) (*IssueTemplate, *RateLimitInfo, error) {
+	if !isMarkdownTemplate(fileName) || strings.Contains(fileName, "/") {
+		return nil, nil, &GitHubError{StatusCode: http.StatusNotFound, Message: getErrorMessage(http.StatusNotFound)}
+	}
+
+	var entry contentEntry
+	rateLimit, err := c.doRequest(ctx, c.endpoint("/repos/%s/%s/contents/%s/%s", url.PathEscape(owner), url.PathEscape(repo), IssueTemplateDir, url.PathEscape(fileName)), &entry)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	if entry.Type != "file" || entry.Encoding != "base64" {
+		return nil, rateLimit, &GitHubError{StatusCode: http.StatusNotFound, Message: getErrorMessage(http.StatusNotFound)}
+	}
+
+	// Contents API の base64 は 60 文字ごとに改行が入る
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(entry.Content, "\n", ""))
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	return parseIssueTemplate(fileName, string(content)), rateLimit, nil
+}
+
+// parseIssueTemplate は Markdown 形式のテンプレートの front matter と本文を解析します。
+// front matter は name / about / title / labels のみを読み取り、labels はカンマ区切り・["a", "b"]・"- a" の行のいずれにも対応します。
+func parseIssueTemplate(fileName, content string) *IssueTemplate {
+	template := &IssueTemplate{FileName: fileName, Name: strings.TrimSuffix(fileName, ".md")}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		template.Body = strings.TrimSpace(content)
+		return template
+	}
+	frontMatter, body, ok := strings.Cut("\n"+rest, "\n---")
+	if !ok {
+		template.Body = strings.TrimSpace(content)
+		return template
+	}
+	// 終わりの "---" の行の残りを除く
+	if _, after, found := strings.Cut(body, "\n"); found {
+		body = after
+	} else {
+		body = ""
+	}
+	template.Body = strings.TrimSpace(body)
+
+	key := ""
+	for _, line := range strings.Split(frontMatter, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if item, isItem := strings.CutPrefix(trimmed, "- "); isItem {
+			// 直前のキーの YAML のリスト
+			if key == "labels" {
+				if label := unquoteYAML(item); label != "" {
+					template.Labels = append(template.Labels, label)
+				}
+			}
+			continue
+		}
+
+		name, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "name":
+			if value = unquoteYAML(value); value != "" {
+				template.Name = value
+			}
+		case "about":
+			template.About = unquoteYAML(value)
+		case "title":
+			template.Title = unquoteYAML(value)
+		case "labels":
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+			for _, label := range strings.Split(value, ",") {
+				if label = unquoteYAML(label); label != "" {
+					template.Labels = append(template.Labels, label)
+				}
+			}
+		}
+	}
+	return template
+}
+
+// unquoteYAML は YAML のスカラー値の前後の空白と引用符を取り除きます
+func unquoteYAML(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		// 引用符の内側の空白は残す（title: "[BUG] " など）
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,149 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Issue の状態の変更に指定する値
+const (
+	IssueStateValueOpen   = "open"
+	IssueStateValueClosed = "closed"
+)
+
+// doWriteRequest は POST / PATCH / DELETE のリクエストを実行し、レスポンスを result にデコードします。
+// payload は JSON に変換して送信します（nil の場合はボディなし）。書き込みにはレスポンスキャッシュを利用しません。
+// Issue が重複して作成されないよう、ネットワークエラーや 5xx では再送しません。
+func (c *Client) doWriteRequest(ctx context.Context, method, url string, payload, result interface{}) (*RateLimitInfo, error) {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, rateLimit, err := sendWithRetry(ctx, c.httpClient, c.retry, c.rateLimits, resourceForURL(url), false, func() (*http.Request, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reader)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
+	if err != nil {
+		return rateLimit, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return rateLimit, &GitHubError{
+			StatusCode: resp.StatusCode,
+			Message:    getErrorMessage(resp.StatusCode),
+		}
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return rateLimit, err
+	}
+	if len(respBody) == 0 {
+		return rateLimit, nil
+	}
+	return rateLimit, decodeBody(respBody, result)
+}
+
+// issueURL は /repos/{owner}/{repo}/issues/{number} 以下のエンドポイントを返します
+func (c *Client) issueURL(owner, repo string, number int, suffix string) string {
+	return c.endpoint("/repos/%s/%s/issues/%d%s", url.PathEscape(owner), url.PathEscape(repo), number, suffix)
+}
+
+// GetIssue は Issue (または Pull Request) を1件取得します
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, *RateLimitInfo, error) {
+	var issue Issue
+	rateLimit, err := c.doRequest(ctx, c.issueURL(owner, repo, number, ""), &issue)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	issue.Repository = &Repository{FullName: owner + "/" + repo}
+	return &issue, rateLimit, nil
+}
+
+// UpdateIssueState は Issue の状態を state (IssueStateValueOpen / IssueStateValueClosed) に変更します
+func (c *Client) UpdateIssueState(ctx context.Context, owner, repo string, number int, state string) (*Issue, *RateLimitInfo, error) {
+	var issue Issue
+	payload := map[string]string{"state": state}
+	rateLimit, err := c.doWriteRequest(ctx, http.MethodPatch, c.issueURL(owner, repo, number, ""), payload, &issue)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	issue.Repository = &Repository{FullName: owner + "/" + repo}
+	return &issue, rateLimit, nil
+}
+
+// AddAssignees は Issue に担当者を追加します。割り当てられないユーザーは GitHub 側で無視されます。
+func (c *Client) AddAssignees(ctx context.Context, owner, repo string, number int, logins []string) (*Issue, *RateLimitInfo, error) {
+	var issue Issue
+	payload := map[string][]string{"assignees": logins}
+	rateLimit, err := c.doWriteRequest(ctx, http.MethodPost, c.issueURL(owner, repo, number, "/assignees"), payload, &issue)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	issue.Repository = &Repository{FullName: owner + "/" + repo}
+	return &issue, rateLimit, nil
+}
+
+// AddLabels は Issue にラベルを追加し、追加後のラベルを返します
+func (c *Client) AddLabels(ctx context.Context, owner, repo string, number int, labels []string) ([]Label, *RateLimitInfo, error) {
+	var result []Label
+	payload := map[string][]string{"labels": labels}
+	rateLimit, err := c.doWriteRequest(ctx, http.MethodPost, c.issueURL(owner, repo, number, "/labels"), payload, &result)
+	return result, rateLimit, err
+}
+
+// RemoveLabel は Issue からラベルを外し、残りのラベルを返します
+func (c *Client) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) ([]Label, *RateLimitInfo, error) {
+	var result []Label
+	rateLimit, err := c.doWriteRequest(ctx, http.MethodDelete, c.issueURL(owner, repo, number, "/labels/"+url.PathEscape(label)), nil, &result)
+	return result, rateLimit, err
+}
+
+// GetRepositoryLabels はリポジトリのラベルを最大 100 件取得します
+func (c *Client) GetRepositoryLabels(ctx context.Context, owner, repo string) ([]Label, *RateLimitInfo, error) {
+	var labels []Label
+	rateLimit, err := c.doRequest(ctx, c.endpoint("/repos/%s/%s/labels?per_page=100", url.PathEscape(owner), url.PathEscape(repo)), &labels)
+	return labels, rateLimit, err
+}
+
+// NewIssue は作成する Issue の内容です
+type NewIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// CreateIssue はリポジトリに Issue を作成します。
+// 指定したラベルのうち、リポジトリに存在しないものは書き込み権限がある場合のみ新しく作成されます。
+func (c *Client) CreateIssue(ctx context.Context, owner, repo string, newIssue NewIssue) (*Issue, *RateLimitInfo, error) {
+	var issue Issue
+	rateLimit, err := c.doWriteRequest(ctx, http.MethodPost, c.endpoint("/repos/%s/%s/issues", url.PathEscape(owner), url.PathEscape(repo)), newIssue, &issue)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	issue.Repository = &Repository{FullName: owner + "/" + repo}
+	return &issue, rateLimit, nil
+}
@@ -0,0 +1,221 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDeviceCodeExpired はユーザーが認可する前にデバイスコードの有効期限が切れた場合に返されます
+	ErrDeviceCodeExpired = errors.New("device code expired")
+	// ErrAuthorizationDenied はユーザーが認可画面でキャンセルした場合に返されます
+	ErrAuthorizationDenied = errors.New("authorization denied by user")
+	// ErrRefreshTokenInvalid はリフレッシュトークンが無効または期限切れの場合に返されます
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+)
+
+// deviceFlowSlowDownInterval は slow_down を受け取った場合にポーリング間隔へ加算する時間です
+const deviceFlowSlowDownInterval = 5 * time.Second
+
+// DeviceCode はデバイス認可フローの開始時に発行されるコードです
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"` // 秒
+	Interval        int    `json:"interval"`   // ポーリングの最小間隔 (秒)
+}
+
+// OAuthToken は OAuth で発行されたユーザーアクセストークンです。
+// 有効期限のないトークン (OAuth App や期限切れを無効にした GitHub App) の場合、ExpiresAt はゼロ値です。
+type OAuthToken struct {
+	AccessToken           string
+	RefreshToken          string
+	ExpiresAt             time.Time
+	RefreshTokenExpiresAt time.Time
+	Scope                 string
+}
+
+// oauthTokenResponse は /login/oauth/access_token のレスポンスです。
+// エラーの場合も 200 で返り、error フィールドに理由が入ります。
+type oauthTokenResponse struct {
+	AccessToken           string `json:"access_token"`
+	RefreshToken          string `json:"refresh_token"`
+	ExpiresIn             int    `json:"expires_in"`
+	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in"`
+	Scope                 string `json:"scope"`
+	Error                 string `json:"error"`
+	ErrorDescription      string `json:"error_description"`
+	Interval              int    `json:"interval"`
+}
+
+func (r *oauthTokenResponse) token(now time.Time) *OAuthToken {
+	token := &OAuthToken{
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+		Scope:        r.Scope,
+	}
+	if r.ExpiresIn > 0 {
+		token.ExpiresAt = now.Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	if r.RefreshTokenExpiresIn > 0 {
+		token.RefreshTokenExpiresAt = now.Add(time.Duration(r.RefreshTokenExpiresIn) * time.Second)
+	}
+	return token
+}
+
+// OAuthApp は OAuth App / GitHub App のクライアントIDを使い、デバイス認可フローでユーザーアクセストークンを取得します
+type OAuthApp struct {
+	httpClient   *http.Client
+	clientID     string
+	clientSecret string
+	webBaseURL   string
+}
+
+// NewOAuthApp は OAuthApp を生成します。
+// clientSecret はトークンの更新に使います（GitHub App の期限付きトークンを更新する場合は必須です）。
+// baseURL には REST API のベースURLを指定し、空の場合は github.com を利用します。
+func NewOAuthApp(clientID, clientSecret, baseURL string) *OAuthApp {
+	return &OAuthApp{
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		webBaseURL:   webBaseURL(baseURL),
+	}
+}
+
+// webBaseURL は REST API のベースURLから OAuth のエンドポイントがあるホストを求めます。
+// github.com は "https://github.com"、GitHub Enterprise Server は "https://host" です。
+func webBaseURL(baseURL string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" || baseURL == DefaultBaseURL {
+		return "https://github.com"
+	}
+	return strings.TrimSuffix(baseURL, "/api/v3")
+}
+
+// postForm はフォームを POST して JSON レスポンスを result に読み込みます
+func (a *OAuthApp) postForm(ctx context.Context, path string, form url.Values, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", a.webBaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &GitHubError{
+			StatusCode: resp.StatusCode,
+			Message:    getErrorMessage(resp.StatusCode),
+		}
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// RequestDeviceCode はデバイス認可フローを開始し、ユーザーに入力してもらうコードを発行します。
+// scopes は OAuth App の場合に要求するスコープです（GitHub App では無視されます）。
+func (a *OAuthApp) RequestDeviceCode(ctx context.Context, scopes []string) (*DeviceCode, error) {
+	form := url.Values{"client_id": {a.clientID}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	var code struct {
+		DeviceCode
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := a.postForm(ctx, "/login/device/code", form, &code); err != nil {
+		return nil, err
+	}
+	if code.Error != "" {
+		return nil, fmt.Errorf("device code request failed: %s: %s", code.Error, code.ErrorDescription)
+	}
+	return &code.DeviceCode, nil
+}
+
+// PollAccessToken はユーザーが認可するまでポーリングし、アクセストークンを返します。
+// デバイスコードの有効期限が切れた場合は ErrDeviceCodeExpired、キャンセルされた場合は ErrAuthorizationDenied を返します。
+func (a *OAuthApp) PollAccessToken(ctx context.Context, code *DeviceCode) (*OAuthToken, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = deviceFlowSlowDownInterval
+	}
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	form := url.Values{
+		"client_id":   {a.clientID},
+		"device_code": {code.DeviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+	for {
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrDeviceCodeExpired
+		}
+
+		var resp oauthTokenResponse
+		if err := a.postForm(ctx, "/login/oauth/access_token", form, &resp); err != nil {
+			return nil, err
+		}
+
+		switch resp.Error {
+		case "":
+			return resp.token(time.Now()), nil
+		case "authorization_pending":
+			// ユーザーがまだコードを入力していない
+		case "slow_down":
+			if resp.Interval > 0 {
+				interval = time.Duration(resp.Interval) * time.Second
+			} else {
+				interval += deviceFlowSlowDownInterval
+			}
+		case "expired_token":
+			return nil, ErrDeviceCodeExpired
+		case "access_denied":
+			return nil, ErrAuthorizationDenied
+		default:
+			return nil, fmt.Errorf("device flow failed: %s: %s", resp.Error, resp.ErrorDescription)
+		}
+	}
+}
+
+// RefreshToken はリフレッシュトークンを使ってアクセストークンを更新します。
+// リフレッシュトークンは1回しか使えないため、返されたトークンで保存済みの値を置き換えてください。
+func (a *OAuthApp) RefreshToken(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	form := url.Values{
+		"client_id":     {a.clientID},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	if a.clientSecret != "" {
+		form.Set("client_secret", a.clientSecret)
+	}
+
+	var resp oauthTokenResponse
+	if err := a.postForm(ctx, "/login/oauth/access_token", form, &resp); err != nil {
+		return nil, err
+	}
+	switch resp.Error {
+	case "":
+		return resp.token(time.Now()), nil
+	case "bad_refresh_token":
+		return nil, ErrRefreshTokenInvalid
+	default:
+		return nil, fmt.Errorf("token refresh failed: %s: %s", resp.Error, resp.ErrorDescription)
+	}
+}
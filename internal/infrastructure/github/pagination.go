@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// pageLinks は Link ヘッダー (RFC 5988) から取り出したページ情報です
+type pageLinks struct {
+	hasNext  bool // rel="next" がある（次のページが存在する）
+	lastPage int  // rel="last" のページ番号（不明な場合は 0）
+}
+
+// parseLinkHeader は `<https://...?page=2>; rel="next", <https://...?page=5>; rel="last"` 形式の Link ヘッダーを解析します。
+// Link ヘッダーがない場合は1ページのみのレスポンスです。
+func parseLinkHeader(header string) pageLinks {
+	var links pageLinks
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		target := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+
+		for _, param := range segments[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "rel=") {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(strings.TrimPrefix(param, "rel="), `"`)) {
+				switch rel {
+				case "next":
+					links.hasNext = true
+				case "last":
+					links.lastPage = pageNumberFromURL(target)
+				}
+			}
+		}
+	}
+	return links
+}
+
+// pageNumberFromURL はURLの page パラメータを返します（取得できない場合は 0）
+func pageNumberFromURL(raw string) int {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return 0
+	}
+	page, err := strconv.Atoi(u.Query().Get("page"))
+	if err != nil {
+		return 0
+	}
+	return page
+}
+
+// ErrPageBudgetExceeded は1コマンドで取得できるページ数の上限に達した場合に返されます
+var ErrPageBudgetExceeded = errors.New("page budget exceeded")
+
+// PageBudget は1コマンドの間に取得できるページ数の上限です。
+// 同じ PageBudget を設定した ctx のリクエスト（並行して取得するリポジトリを含む）で共有されます。
+type PageBudget struct {
+	mu        sync.Mutex
+	remaining int
+}
+
+// NewPageBudget は最大 maxPages ページまで取得できる PageBudget を生成します
+func NewPageBudget(maxPages int) *PageBudget {
+	return &PageBudget{remaining: maxPages}
+}
+
+// take は1ページ分を消費します。上限に達している場合は false を返します。
+func (b *PageBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.remaining <= 0 {
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// PageProgress はページ取得の進捗です
+type PageProgress struct {
+	Request  string // ページングの対象を識別するURL（1ページ目のURL）
+	Page     int    // 取得済みのページ番号
+	LastPage int    // 最終ページ番号（Link ヘッダーから判明しない場合は 0）
+}
+
+type pageBudgetKey struct{}
+type pageProgressKey struct{}
+
+// WithPageBudget は ctx にページ数の上限を設定します
+func WithPageBudget(ctx context.Context, budget *PageBudget) context.Context {
+	return context.WithValue(ctx, pageBudgetKey{}, budget)
+}
+
+// WithPageProgress は ctx にページ取得の進捗を受け取る関数を設定します。
+// 関数は複数の goroutine から呼び出されることがあります。
+func WithPageProgress(ctx context.Context, fn func(PageProgress)) context.Context {
+	return context.WithValue(ctx, pageProgressKey{}, fn)
+}
+
+func pageBudgetFrom(ctx context.Context) *PageBudget {
+	budget, _ := ctx.Value(pageBudgetKey{}).(*PageBudget)
+	return budget
+}
+
+func reportPageProgress(ctx context.Context, progress PageProgress) {
+	if fn, ok := ctx.Value(pageProgressKey{}).(func(PageProgress)); ok && fn != nil {
+		fn(progress)
+	}
+}
+
+// fetchPage は配列を返すエンドポイントの1ページ分を取得します
+func fetchPage[T any](ctx context.Context, c *Client, url string) ([]T, *RateLimitInfo, pageLinks, error) {
+	var items []T
+	rateLimit, links, err := c.doPageRequest(ctx, url, &items)
+	return items, rateLimit, links, err
+}
+
+// collectAllPages は Link ヘッダーの rel="next" がなくなるまで全ページを取得します。
+// request は進捗の通知でページングの対象を識別するためのURLです。
+// ctx のタイムアウトやキャンセル、PageBudget の上限で中断された場合は、それまでに取得できた項目とエラーを返します。
+func collectAllPages[T any](ctx context.Context, request string, fetch func(page int) ([]T, *RateLimitInfo, pageLinks, error)) ([]T, *RateLimitInfo, error) {
+	var allItems []T
+	var lastRateLimit *RateLimitInfo
+	budget := pageBudgetFrom(ctx)
+
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return allItems, lastRateLimit, err
+		}
+		if budget != nil && !budget.take() {
+			return allItems, lastRateLimit, ErrPageBudgetExceeded
+		}
+
+		items, rateLimit, links, err := fetch(page)
+		if err != nil {
+			if ctx.Err() != nil {
+				return allItems, lastRateLimit, ctx.Err()
+			}
+			return nil, rateLimit, err
+		}
+
+		if rateLimit != nil {
+			lastRateLimit = rateLimit
+		}
+
+		allItems = append(allItems, items...)
+
+		lastPage := links.lastPage
+		if !links.hasNext {
+			lastPage = page
+		}
+		reportPageProgress(ctx, PageProgress{Request: request, Page: page, LastPage: lastPage})
+
+		if !links.hasNext {
+			break
+		}
+	}
+
+	return allItems, lastRateLimit, nil
+}
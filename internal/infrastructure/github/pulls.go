@@ -0,0 +1,126 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+// PullRequest は /repos/{owner}/{repo}/pulls 系エンドポイントの Pull Request です
+type PullRequest struct {
+	Number             int               `json:"number"`
+	Title              string            `json:"title"`
+	HTMLURL            string            `json:"html_url"`
+	State              string            `json:"state"`
+	Draft              bool              `json:"draft"`
+	User               User              `json:"user"`
+	RequestedReviewers []User            `json:"requested_reviewers"`
+	RequestedTeams     []Team            `json:"requested_teams"`
+	Labels             []Label           `json:"labels"`
+	Head               PullRequestBranch `json:"head"`
+	Base               PullRequestBranch `json:"base"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// RepositoryFullName は Pull Request のマージ先リポジトリ名 (owner/repo) を返します
+func (p PullRequest) RepositoryFullName() string {
+	if p.Base.Repo == nil {
+		return ""
+	}
+	return p.Base.Repo.FullName
+}
+
+type PullRequestBranch struct {
+	Ref  string      `json:"ref"`
+	SHA  string      `json:"sha"`
+	Repo *Repository `json:"repo"`
+}
+
+type Team struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Review は Pull Request のレビューです。State は APPROVED / CHANGES_REQUESTED / COMMENTED / DISMISSED / PENDING のいずれかです。
+type Review struct {
+	User        User      `json:"user"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// CombinedStatus はコミットステータス (Status API) の集約結果です。State は success / pending / failure のいずれかです。
+type CombinedStatus struct {
+	State      string `json:"state"`
+	TotalCount int    `json:"total_count"`
+}
+
+// CheckRun は Checks API のチェック実行結果です
+type CheckRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+type checkRunsResponse struct {
+	TotalCount int        `json:"total_count"`
+	CheckRuns  []CheckRun `json:"check_runs"`
+}
+
+func (c *Client) GetRepositoryPullRequests(ctx context.Context, owner, repo string, page, perPage int) ([]PullRequest, *RateLimitInfo, error) {
+	pulls, rateLimit, _, err := fetchPage[PullRequest](ctx, c, c.repositoryPullRequestsURL(owner, repo, page, perPage))
+	return pulls, rateLimit, err
+}
+
+func (c *Client) repositoryPullRequestsURL(owner, repo string, page, perPage int) string {
+	return c.endpoint("/repos/%s/%s/pulls?page=%d&per_page=%d&state=open", owner, repo, page, perPage)
+}
+
+func (c *Client) GetAllRepositoryPullRequests(ctx context.Context, owner, repo string) ([]PullRequest, *RateLimitInfo, error) {
+	return collectAllPages(ctx, c.repositoryPullRequestsURL(owner, repo, 1, maxPerPage), func(page int) ([]PullRequest, *RateLimitInfo, pageLinks, error) {
+		return fetchPage[PullRequest](ctx, c, c.repositoryPullRequestsURL(owner, repo, page, maxPerPage))
+	})
+}
+
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, *RateLimitInfo, error) {
+	url := c.endpoint("/repos/%s/%s/pulls/%d", owner, repo, number)
+
+	var pull PullRequest
+	rateLimit, err := c.doRequest(ctx, url, &pull)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	return &pull, rateLimit, nil
+}
+
+// GetPullRequestReviews は Pull Request のレビューを古い順にすべて取得します
+func (c *Client) GetPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]Review, *RateLimitInfo, error) {
+	reviewsURL := func(page int) string {
+		return c.endpoint("/repos/%s/%s/pulls/%d/reviews?page=%d&per_page=%d", owner, repo, number, page, maxPerPage)
+	}
+	return collectAllPages(ctx, reviewsURL(1), func(page int) ([]Review, *RateLimitInfo, pageLinks, error) {
+		return fetchPage[Review](ctx, c, reviewsURL(page))
+	})
+}
+
+// GetCombinedStatus は ref に対するコミットステータスの集約結果を取得します
+func (c *Client) GetCombinedStatus(ctx context.Context, owner, repo, ref string) (*CombinedStatus, *RateLimitInfo, error) {
+	url := c.endpoint("/repos/%s/%s/commits/%s/status", owner, repo, ref)
+
+	var status CombinedStatus
+	rateLimit, err := c.doRequest(ctx, url, &status)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	return &status, rateLimit, nil
+}
+
+// GetCheckRuns は ref に対するチェック実行結果を取得します（最大 100 件）
+func (c *Client) GetCheckRuns(ctx context.Context, owner, repo, ref string) ([]CheckRun, *RateLimitInfo, error) {
+	url := c.endpoint("/repos/%s/%s/commits/%s/check-runs?per_page=%d", owner, repo, ref, maxPerPage)
+
+	var resp checkRunsResponse
+	rateLimit, err := c.doRequest(ctx, url, &resp)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	return resp.CheckRuns, rateLimit, nil
+}
@@ -0,0 +1,245 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate Limit のリソース種別（X-RateLimit-Resource ヘッダーの値）
+const (
+	RateLimitResourceCore    = "core"
+	RateLimitResourceSearch  = "search"
+	RateLimitResourceGraphQL = "graphql"
+)
+
+// RetryPolicy は一時的なエラー（5xx・セカンダリ Rate Limit・ネットワークエラー）のリトライ方針です
+type RetryPolicy struct {
+	MaxRetries int           // 最大リトライ回数（0 の場合はリトライしない）
+	BaseDelay  time.Duration // 1回目のリトライまでの待機時間。以降は指数的に増加します
+	MaxDelay   time.Duration // 1回あたりの最大待機時間
+
+	// MaxRateLimitWait は Rate Limit のリセットを待つ最大時間です。
+	// リセットまでこれより長くかかる場合は待たずに RateLimitError を返します。
+	MaxRateLimitWait time.Duration
+}
+
+// DefaultRetryPolicy は既定のリトライ方針です
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:       3,
+	BaseDelay:        1 * time.Second,
+	MaxDelay:         30 * time.Second,
+	MaxRateLimitWait: 10 * time.Second,
+}
+
+// WithRetryPolicy はリトライ方針を設定します
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *clientOptions) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimitState は Rate Limit の状態を保持する先を設定します（既定はクライアントごと）
+func WithRateLimitState(state *RateLimitState) Option {
+	return func(c *clientOptions) {
+		c.rateLimits = state
+	}
+}
+
+// ErrRateLimitExceeded は Rate Limit を使い切った場合に返されるエラーです（errors.Is で判定できます）
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// RateLimitError は Rate Limit を使い切り、リセットまで待てない場合に返されます
+type RateLimitError struct {
+	Resource string
+	ResetAt  time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API rate limit exceeded (resource: %s, reset: %s)", e.Resource, e.ResetAt.Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimitExceeded
+}
+
+// RateLimitState は直近の Rate Limit をリソースごとに保持します。
+// 同じトークンのクライアントで共有すると（WithRateLimitState）、使い切ったリソースへのリクエストをリセットまで送らずに失敗させます。
+type RateLimitState struct {
+	mu     sync.Mutex
+	latest map[string]*RateLimitInfo
+}
+
+func (s *RateLimitState) observe(rateLimit *RateLimitInfo) {
+	if rateLimit == nil || rateLimit.Resource == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latest == nil {
+		s.latest = make(map[string]*RateLimitInfo)
+	}
+	s.latest[rateLimit.Resource] = rateLimit
+}
+
+// exhaustedUntil はリソースの Rate Limit を使い切っている場合にリセット時刻を返します
+func (s *RateLimitState) exhaustedUntil(resource string, now time.Time) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rateLimit, ok := s.latest[resource]
+	if !ok || rateLimit.Remaining > 0 || !rateLimit.ResetAt.After(now) {
+		return time.Time{}, false
+	}
+	return rateLimit.ResetAt, true
+}
+
+// resourceForURL はリクエストURLが消費する Rate Limit のリソース種別を返します
+func resourceForURL(url string) string {
+	if strings.Contains(url, "/search/") {
+		return RateLimitResourceSearch
+	}
+	if strings.HasSuffix(url, "/graphql") {
+		return RateLimitResourceGraphQL
+	}
+	return RateLimitResourceCore
+}
+
+// sendWithRetry はリクエストを送信し、一時的なエラーの場合はバックオフしながら再送します。
+// newRequest はリトライごとに新しいリクエストを生成します。
+// idempotent が false（Issue の作成などの書き込み）の場合、ネットワークエラーや 5xx ではリクエストが処理済みの可能性があるため再送せず、
+// Retry-After で待機時間が指定された Rate Limit の場合だけ再送します。
+// リトライ回数を使い切った場合は最後のレスポンスをそのまま返します（呼び出し側で Body を閉じる必要があります）。
+// 返す RateLimitInfo は最後に受け取ったレスポンスのものです。
+func sendWithRetry(ctx context.Context, httpClient *http.Client, policy RetryPolicy, state *RateLimitState, resource string, idempotent bool, newRequest func() (*http.Request, error)) (*http.Response, *RateLimitInfo, error) {
+	var rateLimit *RateLimitInfo
+	for attempt := 0; ; attempt++ {
+		// 使い切っている場合はリセットまで待つか、待てなければ送信せずに失敗させる
+		if resetAt, exhausted := state.exhaustedUntil(resource, time.Now()); exhausted {
+			wait := time.Until(resetAt) + time.Second
+			if wait > policy.MaxRateLimitWait {
+				return nil, rateLimit, &RateLimitError{Resource: resource, ResetAt: resetAt}
+			}
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, rateLimit, err
+			}
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return nil, rateLimit, err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			if !idempotent || ctx.Err() != nil || attempt >= policy.MaxRetries {
+				return nil, rateLimit, err
+			}
+			if err := sleepContext(ctx, backoffDelay(policy, attempt)); err != nil {
+				return nil, rateLimit, err
+			}
+			continue
+		}
+
+		rateLimit = parseRateLimit(resp)
+		state.observe(rateLimit)
+
+		delay, retryable, rateLimitErr := retryDelay(resp, rateLimit, policy, attempt, idempotent)
+		if rateLimitErr != nil {
+			resp.Body.Close()
+			return nil, rateLimit, rateLimitErr
+		}
+		if !retryable || attempt >= policy.MaxRetries {
+			return resp, rateLimit, nil
+		}
+
+		resp.Body.Close()
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, rateLimit, err
+		}
+	}
+}
+
+// retryDelay はレスポンスがリトライ対象かどうかと、次のリトライまでの待機時間を返します。
+// プライマリ Rate Limit の枯渇でリセットまで待てない場合は RateLimitError を返します。
+// idempotent が false の場合は Retry-After が指定された Rate Limit だけをリトライ対象にします。
+func retryDelay(resp *http.Response, rateLimit *RateLimitInfo, policy RetryPolicy, attempt int, idempotent bool) (time.Duration, bool, error) {
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		// セカンダリ Rate Limit は Retry-After で待機時間が指定される
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if retryAfter > policy.MaxRateLimitWait {
+				return 0, false, &RateLimitError{Resource: rateLimit.Resource, ResetAt: time.Now().Add(retryAfter)}
+			}
+			return retryAfter, true, nil
+		}
+		// プライマリ Rate Limit の枯渇はリセット時刻まで待つ
+		if idempotent && resp.Header.Get("X-RateLimit-Remaining") == "0" && !rateLimit.ResetAt.IsZero() {
+			wait := time.Until(rateLimit.ResetAt) + time.Second
+			if wait > policy.MaxRateLimitWait {
+				return 0, false, &RateLimitError{Resource: rateLimit.Resource, ResetAt: rateLimit.ResetAt}
+			}
+			return wait, true, nil
+		}
+		// 権限不足などの 403 はリトライしない
+		return 0, false, nil
+	case resp.StatusCode >= 500 && idempotent:
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok && retryAfter <= policy.MaxDelay {
+			return retryAfter, true, nil
+		}
+		return backoffDelay(policy, attempt), true, nil
+	default:
+		return 0, false, nil
+	}
+}
+
+// backoffDelay は指数バックオフにジッターを加えた待機時間を返します（BaseDelay * 2^attempt の 50〜100%）
+func backoffDelay(policy RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay << attempt
+	if delay <= 0 || delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter は秒数または HTTP 日付形式の Retry-After ヘッダーを解析します
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleepContext は ctx が終了するまでの範囲で d だけ待機します
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
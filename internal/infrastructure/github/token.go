@@ -0,0 +1,131 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// impliedScopes は上位のスコープに含まれるスコープです（例: admin:org は read:org を含む）
+var impliedScopes = map[string][]string{
+	"read:org":  {"write:org", "admin:org"},
+	"write:org": {"admin:org"},
+	"read:user": {"user"},
+}
+
+// tokenExpirationLayouts は github-authentication-token-expiration ヘッダーの日時形式です
+var tokenExpirationLayouts = []string{
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+}
+
+// TokenInfo はトークンの検証時に取得できる認証ユーザー・スコープ・有効期限です
+type TokenInfo struct {
+	Login string
+	// Scopes は X-OAuth-Scopes ヘッダーのスコープです。
+	// Fine-grained PAT や GitHub App のトークンはヘッダーを返さないため nil になります。
+	Scopes []string
+	// ExpiresAt は github-authentication-token-expiration ヘッダーの有効期限です（無期限の場合はゼロ値）
+	ExpiresAt time.Time
+}
+
+// HasScope はトークンが scope（またはそれを含む上位のスコープ）を持つかを返します
+func (t *TokenInfo) HasScope(scope string) bool {
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+		for _, implied := range impliedScopes[scope] {
+			if granted == implied {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MissingScopes は required のうちトークンが持たないスコープを返します。
+// スコープを判別できないトークン (Scopes が nil) の場合は nil を返します。
+func (t *TokenInfo) MissingScopes(required []string) []string {
+	if t.Scopes == nil {
+		return nil
+	}
+	var missing []string
+	for _, scope := range required {
+		if !t.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// ValidateToken は /user を呼び出してトークンを検証し、認証ユーザー・スコープ・有効期限を返します。
+// レスポンスヘッダーを読むため、レスポンスキャッシュは利用しません。
+func (c *Client) ValidateToken(ctx context.Context) (*TokenInfo, error) {
+	url := c.endpoint("/user")
+	resp, _, err := sendWithRetry(ctx, c.httpClient, c.retry, c.rateLimits, resourceForURL(url), true, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &GitHubError{
+			StatusCode: resp.StatusCode,
+			Message:    getErrorMessage(resp.StatusCode),
+		}
+	}
+
+	var user User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &TokenInfo{
+		Login:     user.Login,
+		Scopes:    parseOAuthScopes(resp.Header),
+		ExpiresAt: parseTokenExpiration(resp.Header.Get("github-authentication-token-expiration")),
+	}, nil
+}
+
+// parseOAuthScopes は X-OAuth-Scopes ヘッダーを解析します。
+// ヘッダー自体がない場合は nil、スコープが空の場合は空のスライスを返します。
+func parseOAuthScopes(header http.Header) []string {
+	values, ok := header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		return nil
+	}
+	scopes := []string{}
+	for _, value := range values {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// parseTokenExpiration は github-authentication-token-expiration ヘッダーを解析します（解析できない場合はゼロ値）
+func parseTokenExpiration(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range tokenExpirationLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// webhook の配信に付与されるヘッダー
+const (
+	WebhookEventHeader     = "X-GitHub-Event"
+	WebhookDeliveryHeader  = "X-GitHub-Delivery"
+	WebhookSignatureHeader = "X-Hub-Signature-256"
+)
+
+// 通知に使う webhook のイベント (X-GitHub-Event)
+const (
+	WebhookEventPing              = "ping"
+	WebhookEventIssues            = "issues"
+	WebhookEventIssueComment      = "issue_comment"
+	WebhookEventPullRequest       = "pull_request"
+	WebhookEventPullRequestReview = "pull_request_review"
+)
+
+// webhookSignaturePrefix は X-Hub-Signature-256 の値の接頭辞です
+const webhookSignaturePrefix = "sha256="
+
+// VerifyWebhookSignature は X-Hub-Signature-256 の値 ("sha256=<hex>") が、secret で計算した payload の HMAC-SHA256 と一致するかを返します
+func VerifyWebhookSignature(payload []byte, signature, secret string) bool {
+	if secret == "" || !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, webhookSignaturePrefix))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// SignWebhookPayload は secret で payload に署名し、X-Hub-Signature-256 の形式で返します
+func SignWebhookPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookEvent は webhook の配信内容のうち、通知に使う項目です。
+// どの項目が設定されるかはイベントの種類 (X-GitHub-Event) によって異なります。
+type WebhookEvent struct {
+	Action      string              `json:"action"`
+	Repository  *Repository         `json:"repository"`
+	Sender      *User               `json:"sender"`
+	Issue       *Issue              `json:"issue"`        // issues / issue_comment
+	Comment     *WebhookComment     `json:"comment"`      // issue_comment
+	PullRequest *WebhookPullRequest `json:"pull_request"` // pull_request / pull_request_review
+	Review      *WebhookReview      `json:"review"`       // pull_request_review
+	Label       *Label              `json:"label"`        // labeled / unlabeled
+	Assignee    *User               `json:"assignee"`     // assigned / unassigned
+
+	RequestedReviewer *User `json:"requested_reviewer"` // review_requested
+}
+
+// RepositoryFullName は配信元のリポジトリ名 (owner/repo) を返します
+func (e *WebhookEvent) RepositoryFullName() string {
+	if e.Repository == nil {
+		return ""
+	}
+	return e.Repository.FullName
+}
+
+// WebhookComment は issue_comment イベントのコメントです
+type WebhookComment struct {
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	User    User   `json:"user"`
+}
+
+// WebhookPullRequest は pull_request 系イベントの Pull Request です
+type WebhookPullRequest struct {
+	PullRequest
+	Merged bool `json:"merged"`
+}
+
+// WebhookReview は pull_request_review イベントのレビューです。State は approved / changes_requested / commented のいずれかです。
+type WebhookReview struct {
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	User    User   `json:"user"`
+}
+
+// ParseWebhookEvent は webhook の配信内容を解析します
+func ParseWebhookEvent(payload []byte) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
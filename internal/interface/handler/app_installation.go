@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /app の操作
+const (
+	AppActionLink   = "link"
+	AppActionUnlink = "unlink"
+	AppActionStatus = "status"
+)
+
+// appCommand は /app コマンドの定義です。サーバーの管理権限を持つメンバーのみが実行できます。
+func appCommand() *discordgo.ApplicationCommand {
+	manageGuild := int64(discordgo.PermissionManageGuild)
+	minInstallationID := float64(1)
+	return &discordgo.ApplicationCommand{
+		Name:                     "app",
+		Description:              "このサーバーで利用する GitHub App のインストールを設定します",
+		DefaultMemberPermissions: &manageGuild,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "実行する操作",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "インストールを紐付け", Value: AppActionLink},
+					{Name: "紐付けを解除", Value: AppActionUnlink},
+					{Name: "確認", Value: AppActionStatus},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "installation_id",
+				Description: "GitHub App のインストールID (action:link で必須)",
+				Required:    false,
+				MinValue:    &minInstallationID,
+			},
+		},
+	}
+}
+
+func (h *DiscordHandler) handleAppCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// DefaultMemberPermissions はサーバー側で上書きできるため、実行時にも権限を確認する
+	if i.Member == nil || i.Member.Permissions&discordgo.PermissionManageGuild == 0 {
+		h.respondWithError(s, i, MsgAppPermissionDenied)
+		return
+	}
+
+	action := AppActionStatus
+	var installationID int64
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "action":
+			action = opt.StringValue()
+		case "installation_id":
+			installationID = opt.IntValue()
+		}
+	}
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	switch action {
+	case AppActionLink:
+		if installationID <= 0 {
+			h.respondWithError(s, i, MsgAppInstallationIDRequired)
+			return
+		}
+		installation, err := h.appInstallationUsecase.Link(ctx, i.GuildID, i.Member.User.ID, installationID, h.issuesUsecase)
+		if err != nil {
+			h.respondWithError(s, i, formatAppLinkError(err))
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgAppLinked, installation.AccountLogin, installation.InstallationID))
+	case AppActionUnlink:
+		if err := h.appInstallationUsecase.Unlink(ctx, i.GuildID); err != nil {
+			h.respondWithError(s, i, MsgAppUnlinkFailed)
+			return
+		}
+		h.respondWithSuccess(s, i, MsgAppUnlinked)
+	default:
+		installation, err := h.appInstallationUsecase.Get(ctx, i.GuildID)
+		if err != nil {
+			h.respondWithError(s, i, MsgAppStatusFailed)
+			return
+		}
+		if installation == nil {
+			h.respondWithSuccess(s, i, MsgAppNotLinked)
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgAppStatus,
+			installation.AccountLogin,
+			installation.InstallationID,
+			formatUserMention(installation.LinkedBy),
+			installation.UpdatedAt.Format("2006-01-02 15:04")))
+	}
+}
+
+// formatAppLinkError はインストールの紐付けに失敗した理由をメッセージに変換します
+func formatAppLinkError(err error) string {
+	if errors.Is(err, usecase.ErrAppNotConfigured) {
+		return MsgAppNotConfigured
+	}
+	if errors.Is(err, usecase.ErrInstallationNotAccessible) {
+		return MsgAppInstallationForbidden
+	}
+	if errors.Is(err, usecase.ErrAppUserTokenRequired) || errors.Is(err, usecase.ErrPersonalTokenRequired) || errors.Is(err, usecase.ErrTokenNotFound) {
+		return MsgAppUserTokenRequired
+	}
+	var ghErr *github.GitHubError
+	if errors.As(err, &ghErr) {
+		return fmt.Sprintf(MsgGitHubAPIError, ghErr.Message)
+	}
+	return MsgAppLinkFailed
+}
+
+// formatUserMention はユーザーIDをメンション形式に変換します
+func formatUserMention(userID string) string {
+	if userID == "" {
+		return "不明"
+	}
+	return fmt.Sprintf("<@%s>", userID)
+}
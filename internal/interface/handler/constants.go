@@ -7,12 +7,25 @@ const (
 	ModalIDToken         = "token_modal"
 	ModalIDExcludeIssues = "exclude_issues_modal"
 	ModalIDExcludeAssign = "exclude_assign_modal"
+	ModalIDPageJump      = "page_jump_modal"    // "page_jump_modal:<セッションID>"
+	ModalIDIssueCreate   = "issue_create_modal" // "issue_create_modal:<owner>/<repo>"
+)
+
+// Discord Component IDs（"<種類>:<データ>" の種類の部分）
+const (
+	ComponentIDPage  = "page"
+	ComponentIDIssue = "issue"
 )
 
 // Discord Input IDs
 const (
-	InputIDToken   = "token_input"
-	InputIDExclude = "exclude_input"
+	InputIDToken       = "token_input"
+	InputIDBaseURL     = "base_url_input"
+	InputIDExclude     = "exclude_input"
+	InputIDPage        = "page_input"
+	InputIDIssueTitle  = "issue_title_input"
+	InputIDIssueBody   = "issue_body_input"
+	InputIDIssueLabels = "issue_labels_input"
 )
 
 // Command Types
@@ -28,37 +41,213 @@ const (
 	MsgExcludeSaved          = "✅ %s用に%d件のリポジトリを除外リストに設定しました"
 	MsgNoIssuesFound         = "📭 Issue が見つかりませんでした"
 	MsgNoAssignedIssuesFound = "📭 割り当てられた Issue は見つかりませんでした"
+	MsgNoPullRequestsFound   = "📭 Pull Request が見つかりませんでした"
+	MsgAppLinked             = "✅ GitHub App のインストール (%s, ID: %d) をこのサーバーに紐付けました。トークン未登録のメンバーはこのインストールで GitHub API を利用します。"
+	MsgAppUnlinked           = "✅ GitHub App のインストールの紐付けを解除しました"
+	MsgAppNotLinked          = "ℹ️ このサーバーには GitHub App のインストールが紐付けられていません"
+	MsgAppStatus             = "ℹ️ GitHub App のインストール: %s (ID: %d)\n紐付けたユーザー: %s\n更新日時: %s"
+	MsgLoginStarted          = "🔑 %s を開き、次のコードを入力して GitHub へのアクセスを許可してください。\nコード: `%s` (有効期限: %s)\n許可が完了すると、このメッセージが更新されます。"
+	MsgTokenStatus           = "🔐 GitHub Token の状態:\n- 種類: %s\n- ユーザー: %s\n- スコープ: %s\n- 有効期限: %s\n- API: %s\n- 登録日時: %s"
+	MsgTokenStatusInvalid    = "\n⚠️ このトークンは取り消されたか期限切れのため無効です (%s に検知)。再登録してください。"
+	MsgTokenStatusAppOnly    = "🔐 個人のトークンは登録されていません。GitHub App のインストール (%s) で GitHub API を利用します。\n`/assign` などを使うには `/login` または `/setting action:token` でトークンを登録してください。"
+	MsgScheduleCreated       = "✅ 定期ダイジェスト #%d (%s) を登録しました。次回: %s"
+	MsgScheduleDeleted       = "✅ 定期ダイジェスト #%d を削除しました"
+	MsgScheduleList          = "📅 登録済みの定期ダイジェスト:"
+	MsgNoSchedules           = "📭 定期ダイジェストは登録されていません。`/schedule action:add cron:0 9 * * mon-fri` で登録できます。"
+	MsgDigestHeader          = "📬 %s 定期ダイジェスト #%d (%s)"
+	MsgDigestBaseline        = "%s\n👀 変更の監視を開始しました (現在 %d 件)。次回から新規・クローズ・割り当て・ラベル変更があった Issue だけを投稿します。"
+	MsgDigestChanges         = "%s\n🔔 前回からの変更: %d 件"
+	MsgLoginCompleted        = "✅ GitHub にログインし、トークンを登録しました"
+	MsgWebhookCreated        = "✅ webhook #%d (`%s` → %s) を登録しました。GitHub のリポジトリまたは Organization の Settings → Webhooks で次のように設定してください。\n- Payload URL: `%s`\n- Content type: `application/json`\n- Secret: `%s`\n- イベント: Issues・Issue comments・Pull requests・Pull request reviews\n⚠️ Secret はこのメッセージでのみ表示されます。"
+	MsgWebhookDeleted        = "✅ webhook #%d を削除しました。GitHub 側の webhook も削除してください。"
+	MsgWebhookList           = "🔔 このサーバーの webhook:"
+	MsgWebhookFeedsOnly      = "フィードのみ (`/subscribe`)"
+	MsgSubscriptionCreated   = "✅ フィード #%d を登録しました: %s\n投稿には、このサーバーで対象リポジトリの webhook (`/webhook action:add`) が登録されている必要があります。"
+	MsgSubscriptionDeleted   = "✅ フィード #%d を削除しました"
+	MsgSubscriptionList      = "📡 このサーバーのフィード:"
+	MsgNoSubscriptions       = "📭 フィードは登録されていません。`/subscribe action:add repository:owner/repo events:issue_opened labels:bug` で登録できます。"
+	MsgNoWebhooks            = "📭 webhook は登録されていません。`/webhook action:add repository:owner/repo` で登録できます。"
+	MsgQuerySaved            = "✅ 条件 **%s** を保存しました: %s %s\n`/query action:run name:<名前>` で実行できます。"
+	MsgQueryUpdated          = "✅ 条件 **%s** を上書き保存しました: %s %s"
+	MsgQueryDeleted          = "✅ 条件 **%s** を削除しました"
+	MsgQueryDeletedDigests   = "✅ 条件 **%s** と、この条件を使う定期ダイジェスト %d 件を削除しました"
+	MsgQueryList             = "🔖 保存した条件:"
+	MsgNoQueries             = "📭 保存した条件はありません。`/query action:save name:bugs repository:owner/repo labels:bug` で保存できます。"
+	MsgRenderModeSaved       = "✅ 一覧の既定の表示形式を %s にしました。コマンドの `format` オプションで 1 回ごとに変更できます。"
+	MsgRenderModeStatus      = "ℹ️ 一覧の既定の表示形式: %s\n`/setting action:format format:compact` で変更できます。"
+	MsgIssueClosed           = "✅ %s をクローズしました"
+	MsgIssueReopened         = "✅ %s を再オープンしました"
+	MsgIssueAssignedToMe     = "✅ %s を自分に割り当てました"
+	MsgIssueLabelAdded       = "✅ ラベル `%s` を %s に追加しました"
+	MsgIssueLabelRemoved     = "✅ ラベル `%s` を %s から外しました"
+	MsgIssueCreated          = "✅ %s を作成しました"
 )
 
 // User Messages - Errors
 const (
-	MsgTokenNotFound         = "❌ トークンが登録されていません。`/setting` でトークンを登録してください。"
-	MsgTokenValidationFailed = "❌ トークンの検証に失敗しました: %s"
-	MsgTokenSaveFailed       = "❌ トークンの保存に失敗しました"
-	MsgInvalidRepoFormat     = "❌ repository は owner/repo 形式、username 形式、または all を指定してください。"
-	MsgInvalidExcludePattern = "❌ 不正な形式があります: %s\n正しい形式:\n- owner/repo (特定リポジトリ)\n- owner/* (organization全体)\n- owner (owner/*と同じ)"
-	MsgExcludeSaveFailed     = "❌ 除外リポジトリの保存に失敗しました"
-	MsgGitHubAPIError        = "❌ GitHub API エラー: %s"
-	MsgIssueFetchFailed      = "❌ Issue の取得に失敗しました"
+	MsgTokenNotFound             = "❌ トークンが登録されていません。`/login` または `/setting` でトークンを登録するか、管理者に `/app` で GitHub App を紐付けてもらってください。"
+	MsgTokenExpired              = "❌ 登録されているトークンの有効期限が切れました。`/login` または `/setting action:token` で再登録してください。"
+	MsgTokenRevoked              = "❌ 登録されているトークンは取り消されたか期限切れのため無効になっています。`/login` または `/setting action:token` で再登録してください。"
+	MsgTokenMissingScopes        = "❌ トークンに必要なスコープがありません: `%s`\nプライベートリポジトリと Organization の Issue を取得するため、`repo` と `read:org` を付与したトークンを発行し直してください。"
+	MsgTokenStatusFailed         = "❌ トークンの状態の取得に失敗しました"
+	MsgLoginExpired              = "❌ GitHub へのログインの有効期限が切れました。`/login` で再度ログインしてください。"
+	MsgPersonalTokenRequired     = "❌ この操作には個人のトークンが必要です。`/setting` でトークンを登録してください。"
+	MsgTokenValidationFailed     = "❌ トークンの検証に失敗しました: %s"
+	MsgTokenSaveFailed           = "❌ トークンの保存に失敗しました"
+	MsgInvalidBaseURL            = "❌ GitHub API URL の形式が不正です。例: https://github.example.com/api/v3 (github.com の場合は空欄)"
+	MsgInvalidRepoFormat         = "❌ repository は owner/repo 形式、username 形式、または all を指定してください。"
+	MsgInvalidSince              = "❌ since は 2024-04-01 のような日付、または 7d・24h・2w のような現在からの期間で指定してください。"
+	MsgInvalidExcludePattern     = "❌ 不正な形式があります: %s\n正しい形式:\n- owner/repo (特定リポジトリ)\n- owner/* (organization全体)\n- owner (owner/*と同じ)"
+	MsgExcludeSaveFailed         = "❌ 除外リポジトリの保存に失敗しました"
+	MsgGitHubAPIError            = "❌ GitHub API エラー: %s"
+	MsgIssueFetchFailed          = "❌ Issue の取得に失敗しました"
+	MsgIssueFetchTimeout         = "❌ Issue の取得がタイムアウトしました。対象を絞って再実行してください。"
+	MsgPageBudgetExceeded        = "❌ 1回のコマンドで取得できるページ数の上限に達しました。対象を絞って再実行してください。"
+	MsgAppPermissionDenied       = "❌ この操作には「サーバー管理」権限が必要です"
+	MsgAppInstallationIDRequired = "❌ installation_id を指定してください"
+	MsgAppNotConfigured          = "❌ Bot に GitHub App が設定されていません (GITHUB_APP_ID / GITHUB_APP_PRIVATE_KEY)"
+	MsgAppLinkFailed             = "❌ GitHub App のインストールの紐付けに失敗しました"
+	MsgAppUnlinkFailed           = "❌ GitHub App のインストールの紐付け解除に失敗しました"
+	MsgAppStatusFailed           = "❌ GitHub App のインストールの取得に失敗しました"
+	MsgAppInstallationForbidden  = "❌ あなたの GitHub アカウントはこのインストールにアクセスできないため、紐付けできません。インストール先のアカウントのメンバーが実行してください。"
+	MsgAppUserTokenRequired      = "❌ インストールへのアクセスを確認できませんでした。GitHub App のクライアントIDを設定した `/login` でログインしてから実行してください。"
+	MsgRateLimitExceeded         = "❌ GitHub API の Rate Limit を使い切りました。%s 以降に再実行してください。"
+	MsgScheduleCronRequired      = "❌ cron を指定してください。例: `0 9 * * mon-fri` (平日 9:00)"
+	MsgScheduleInvalidCron       = "❌ cron 式の形式が不正です。「分 時 日 月 曜日」の 5 項目で指定してください。例: `0 9 * * mon-fri`、`30 8 1 * *`、`@daily`"
+	MsgScheduleInvalidTimezone   = "❌ タイムゾーンが不正です。`Asia/Tokyo`、`UTC`、`America/New_York` のような IANA のタイムゾーン名を指定してください。"
+	MsgScheduleTooFrequent       = "❌ 定期ダイジェストは 1 時間以上の間隔で指定してください"
+	MsgScheduleLimitReached      = "❌ 定期ダイジェストは 1 人 %d 件まで登録できます。不要なものを `/schedule action:delete` で削除してください。"
+	MsgScheduleIDRequired        = "❌ id を指定してください。ID は `/schedule action:list` で確認できます。"
+	MsgScheduleNotFound          = "❌ 指定された定期ダイジェストが見つかりません"
+	MsgScheduleSaveFailed        = "❌ 定期ダイジェストの保存に失敗しました"
+	MsgScheduleListFailed        = "❌ 定期ダイジェストの取得に失敗しました"
+	MsgManageChannelsRequired    = "❌ この操作には「チャンネルの管理」権限が必要です"
+	MsgWebhookInvalidPattern     = "❌ repository は owner/repo・owner/*・owner のいずれかの形式で指定してください"
+	MsgWebhookNotConfigured      = "❌ Bot で webhook の受信サーバーが起動していません (WEBHOOK_LISTEN_ADDR)"
+	MsgWebhookLimitReached       = "❌ webhook は 1 サーバー %d 件まで登録できます。不要なものを `/webhook action:remove` で削除してください。"
+	MsgWebhookIDRequired         = "❌ id を指定してください。ID は `/webhook action:list` で確認できます。"
+	MsgWebhookNotFound           = "❌ 指定された webhook が見つかりません"
+	MsgWebhookSaveFailed         = "❌ webhook の保存に失敗しました"
+	MsgWebhookListFailed         = "❌ webhook の取得に失敗しました"
+	MsgSubscriptionInvalidEvent  = "❌ 不明なイベントです: `%s`\n指定できるイベント: %s"
+	MsgSubscriptionLimitReached  = "❌ フィードは 1 サーバー %d 件まで登録できます。不要なものを `/subscribe action:remove` で削除してください。"
+	MsgSubscriptionIDRequired    = "❌ id を指定してください。ID は `/subscribe action:list` で確認できます。"
+	MsgSubscriptionNotFound      = "❌ 指定されたフィードが見つかりません"
+	MsgSubscriptionSaveFailed    = "❌ フィードの保存に失敗しました"
+	MsgSubscriptionListFailed    = "❌ フィードの取得に失敗しました"
+	MsgQueryNameRequired         = "❌ name を指定してください。保存した条件は `/query action:list` で確認できます。"
+	MsgQueryInvalidName          = "❌ name は空白を含まない %d 文字以内で指定してください"
+	MsgQueryLimitReached         = "❌ 条件は 1 人 %d 件まで保存できます。不要なものを `/query action:delete` で削除してください。"
+	MsgQueryNotFound             = "❌ 条件 **%s** が見つかりません。保存した条件は `/query action:list` で確認できます。"
+	MsgQuerySaveFailed           = "❌ 条件の保存に失敗しました"
+	MsgQueryListFailed           = "❌ 条件の取得に失敗しました"
+	MsgScheduleQueryRequired     = "❌ scope:query では query に保存した条件の名前を指定してください"
+	MsgPageExpired               = "❌ このページ送りの有効期限が切れました。コマンドを再実行してください。"
+	MsgPageNotOwner              = "❌ ページを送れるのはコマンドを実行したユーザーだけです"
+	MsgPageInvalid               = "❌ ページ番号は 1〜%d で指定してください"
+	MsgRenderModeSaveFailed      = "❌ 表示形式の保存に失敗しました"
+	MsgRenderModeLoadFailed      = "❌ 表示形式の取得に失敗しました"
+	MsgLoginNotConfigured        = "❌ Bot に OAuth のクライアントIDが設定されていません (GITHUB_OAUTH_CLIENT_ID)。`/setting action:token` でトークンを登録してください。"
+	MsgLoginCodeExpired          = "❌ コードの有効期限が切れました。`/login` をやり直してください。"
+	MsgLoginDenied               = "❌ GitHub へのアクセスが許可されませんでした"
+	MsgLoginFailed               = "❌ GitHub へのログインに失敗しました"
+	MsgIssueActionInvalid        = "❌ 操作する Issue を特定できませんでした。一覧を表示し直してください。"
+	MsgIssueRepositoryRequired   = "❌ repository は owner/repo 形式で指定してください"
+	MsgIssueTitleRequired        = "❌ タイトルを入力してください"
+)
+
+// User Messages - Token Alerts
+const (
+	MsgTokenAlertExpiring = "⏰ 登録されている GitHub Token の有効期限が近づいています: %s\n期限が切れる前に `/login` または `/setting action:token` で新しいトークンを登録してください。"
+	MsgTokenAlertExpired  = "⚠️ 登録されている GitHub Token の有効期限が切れました。`/login` または `/setting action:token` で再登録してください。"
+	MsgTokenAlertRevoked  = "⚠️ 登録されている GitHub Token が取り消されたため、無効にしました。`/login` または `/setting action:token` で再登録してください。"
+)
+
+// User Messages - Issue Changes
+const (
+	MsgIssueChangeNew      = "🆕 新規: %s"
+	MsgIssueChangeClosed   = "✅ クローズ (または対象外): %s"
+	MsgIssueChangeAssigned = "👤 割り当て: %s"
+	MsgIssueChangeLabels   = "🏷️ ラベル変更: %s (%s)"
+)
+
+// User Messages - Webhook Events
+const (
+	MsgWebhookIssueOpened            = "🟢 Issue が作成されました"
+	MsgWebhookIssueClosed            = "✅ Issue がクローズされました"
+	MsgWebhookIssueReopened          = "🔄 Issue が再開されました"
+	MsgWebhookIssueAssigned          = "👤 %s に割り当てられました"
+	MsgWebhookIssueLabeled           = "🏷️ ラベル `%s` が追加されました"
+	MsgWebhookCommented              = "💬 コメントが追加されました"
+	MsgWebhookPullRequestOpened      = "🔀 Pull Request が作成されました"
+	MsgWebhookPullRequestDraftOpened = "📝 Draft の Pull Request が作成されました"
+	MsgWebhookPullRequestClosed      = "🚫 Pull Request がマージされずにクローズされました"
+	MsgWebhookPullRequestMerged      = "🎉 Pull Request がマージされました"
+	MsgWebhookPullRequestReopened    = "🔄 Pull Request が再開されました"
+	MsgWebhookPullRequestReady       = "👀 Pull Request がレビュー可能になりました"
+	MsgWebhookReviewRequested        = "🙋 %s にレビューが依頼されました"
+	MsgWebhookReviewApproved         = "✅ 承認されました"
+	MsgWebhookReviewChangesRequested = "❌ 変更が依頼されました"
+	MsgWebhookReviewCommented        = "💬 レビューコメントが追加されました"
+)
+
+// User Messages - Pagination
+const (
+	MsgPageIndicator = "📄 %d / %d ページ (全 %d 件)"
+)
+
+// User Messages - Groups
+const (
+	MsgGroupHeader = "%s %s (%d 件)"
+)
+
+// User Messages - Progress
+const (
+	MsgFetchProgress          = "⏳ 取得中… %d ページ"
+	MsgFetchProgressWithTotal = "⏳ 取得中… %d / %d ページ"
 )
 
 // User Messages - Warnings
 const (
-	MsgRateLimitWarning = "⚠️ API Rate Limit 残り: %d (リセット: %s)"
+	MsgRateLimitWarning       = "⚠️ API Rate Limit 残り: %d (リセット: %s)"
+	MsgRateLimitWarningDetail = "⚠️ API Rate Limit 残り: %d/%d (%s, リセット: %s)"
+	MsgResultsTruncated       = "⚠️ タイムアウト・Rate Limit・取得ページ数の上限により、途中までの結果を表示しています。"
 )
 
 // Discord Limits
 const (
 	MaxEmbedsPerMessage       = 10
+	MaxEmbedCharsPerMessage   = 6000
+	MaxEmbedDescriptionLength = 4096
+	MaxMessageLength          = 2000
 	RateLimitWarningThreshold = 10
 )
 
 // Timeouts
 const (
 	DefaultContextTimeout = 30 * time.Second // GitHub API calls timeout
+	PageSessionTTL        = 30 * time.Minute // pagination state lifetime since the last page turn
+	IssueTemplateTimeout  = 2 * time.Second  // template lookups must finish before the 3s interaction deadline
+	LoginWaitLimit        = 13 * time.Minute // /login stops polling in time to edit the reply before the 15-minute interaction token expires
+)
+
+// Pagination
+const (
+	MaxPageSessions = 500 // pagination states kept in memory; the least recently used one is evicted first
 )
 
 // Discord Embed Colors
 const (
-	ColorGitHubSuccess = 0x238636 // GitHub's green color for success/open issues
+	ColorGitHubSuccess     = 0x238636 // GitHub's green color for success/open issues
+	ColorGitHubPullRequest = 0x1f6feb // GitHub's blue color for pull requests
+	ColorGitHubDraft       = 0x6e7681 // GitHub's gray color for draft pull requests
+	ColorGitHubClosed      = 0x8250df // GitHub's purple color for closed issues / merged pull requests
+	ColorGitHubDanger      = 0xcf222e // GitHub's red color for closed pull requests / requested changes
+)
+
+// Embed Icons
+const (
+	IconIssue            = "🟢"
+	IconPullRequest      = "🔀"
+	IconDraftPullRequest = "📝"
 )
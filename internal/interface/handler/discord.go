@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -14,17 +15,54 @@ import (
 )
 
 type DiscordHandler struct {
-	settingUsecase *usecase.SettingUsecase
-	issuesUsecase  *usecase.IssuesUsecase
+	settingUsecase         *usecase.SettingUsecase
+	issuesUsecase          *usecase.IssuesUsecase
+	pullRequestsUsecase    *usecase.PullRequestsUsecase
+	appInstallationUsecase *usecase.AppInstallationUsecase
+	loginUsecase           *usecase.LoginUsecase
+	scheduleUsecase        *usecase.ScheduleUsecase
+	watchUsecase           *usecase.WatchUsecase
+	webhookUsecase         *usecase.WebhookUsecase
+	subscriptionUsecase    *usecase.SubscriptionUsecase
+	queryUsecase           *usecase.QueryUsecase
+
+	// pages は複数ページの一覧のページ送りの状態です
+	pages *pageStore
+
+	// ctx はシャットダウン時にキャンセルされ、実行中のGitHub API呼び出しを中断します
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
-func NewDiscordHandler(settingUsecase *usecase.SettingUsecase, issuesUsecase *usecase.IssuesUsecase) *DiscordHandler {
+func NewDiscordHandler(settingUsecase *usecase.SettingUsecase, issuesUsecase *usecase.IssuesUsecase, pullRequestsUsecase *usecase.PullRequestsUsecase, appInstallationUsecase *usecase.AppInstallationUsecase, loginUsecase *usecase.LoginUsecase, scheduleUsecase *usecase.ScheduleUsecase, watchUsecase *usecase.WatchUsecase, webhookUsecase *usecase.WebhookUsecase, subscriptionUsecase *usecase.SubscriptionUsecase, queryUsecase *usecase.QueryUsecase) *DiscordHandler {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &DiscordHandler{
-		settingUsecase: settingUsecase,
-		issuesUsecase:  issuesUsecase,
+		settingUsecase:         settingUsecase,
+		issuesUsecase:          issuesUsecase,
+		pullRequestsUsecase:    pullRequestsUsecase,
+		appInstallationUsecase: appInstallationUsecase,
+		loginUsecase:           loginUsecase,
+		scheduleUsecase:        scheduleUsecase,
+		watchUsecase:           watchUsecase,
+		webhookUsecase:         webhookUsecase,
+		subscriptionUsecase:    subscriptionUsecase,
+		queryUsecase:           queryUsecase,
+		pages:                  newPageStore(PageSessionTTL, MaxPageSessions),
+		ctx:                    ctx,
+		cancel:                 cancel,
 	}
 }
 
+// Close は実行中の処理をキャンセルします。Bot のシャットダウン時に呼び出してください。
+func (h *DiscordHandler) Close() {
+	h.cancel()
+}
+
+// newContext はシャットダウンに連動するタイムアウト付きのコンテキストを生成します
+func (h *DiscordHandler) newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(h.ctx, DefaultContextTimeout)
+}
+
 func (h *DiscordHandler) RegisterCommands(s *discordgo.Session) error {
 	commands := []*discordgo.ApplicationCommand{
 		{
@@ -38,9 +76,11 @@ func (h *DiscordHandler) RegisterCommands(s *discordgo.Session) error {
 					Required:    true,
 					Choices: []*discordgo.ApplicationCommandOptionChoice{
 						{Name: "トークン設定", Value: "token"},
+						{Name: "トークン状態確認", Value: "status"},
 						{Name: "通知チャンネル設定", Value: "notification_channel"},
 						{Name: "/issues用 除外リポジトリ設定", Value: "exclude_issues"},
 						{Name: "/assign用 除外リポジトリ設定", Value: "exclude_assign"},
+						{Name: "一覧の表示形式設定", Value: "format"},
 					},
 				},
 				{
@@ -56,24 +96,39 @@ func (h *DiscordHandler) RegisterCommands(s *discordgo.Session) error {
 						{Name: "解除", Value: "clear"},
 					},
 				},
+				settingFormatOption(),
 			},
 		},
 		{
 			Name:        "assign",
 			Description: "自分に割り当てられた Issue を取得します",
+			Options: []*discordgo.ApplicationCommandOption{
+				itemTypeOption(),
+				formatOption(),
+			},
 		},
 		{
 			Name:        "issues",
 			Description: "指定したリポジトリの Issue を取得します",
-			Options: []*discordgo.ApplicationCommandOption{
+			Options: append([]*discordgo.ApplicationCommandOption{
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
 					Name:        "repository",
 					Description: "owner/repo 形式で指定",
 					Required:    true,
 				},
-			},
+				itemTypeOption(),
+				formatOption(),
+			}, issueFilterOptions()...),
 		},
+		pullRequestsCommand(),
+		appCommand(),
+		loginCommand(),
+		scheduleCommand(),
+		webhookCommand(),
+		subscribeCommand(),
+		queryCommand(),
+		issueCommand(),
 	}
 
 	for _, cmd := range commands {
@@ -86,10 +141,42 @@ func (h *DiscordHandler) RegisterCommands(s *discordgo.Session) error {
 	return nil
 }
 
+// itemTypeOption は Issue / Pull Request の種類を選ぶ共通オプションです
+func itemTypeOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "type",
+		Description: "取得する種類 (既定: both)",
+		Required:    false,
+		Choices: []*discordgo.ApplicationCommandOptionChoice{
+			{Name: "Issue のみ", Value: string(github.ItemTypeIssues)},
+			{Name: "Pull Request のみ", Value: string(github.ItemTypePullRequests)},
+			{Name: "両方", Value: string(github.ItemTypeBoth)},
+		},
+	}
+}
+
+// parseItemType はコマンドオプションから取得する種類を読み取ります
+func parseItemType(options []*discordgo.ApplicationCommandInteractionDataOption) github.ItemType {
+	for _, opt := range options {
+		if opt.Name == "type" {
+			switch itemType := github.ItemType(opt.StringValue()); itemType {
+			case github.ItemTypeIssues, github.ItemTypePullRequests, github.ItemTypeBoth:
+				return itemType
+			}
+		}
+	}
+	return github.ItemTypeBoth
+}
+
 func (h *DiscordHandler) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	switch i.Type {
 	case discordgo.InteractionApplicationCommand:
 		h.handleCommand(s, i)
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		h.handleAutocomplete(s, i)
+	case discordgo.InteractionMessageComponent:
+		h.handleComponent(s, i)
 	case discordgo.InteractionModalSubmit:
 		h.handleModalSubmit(s, i)
 	}
@@ -103,6 +190,22 @@ func (h *DiscordHandler) handleCommand(s *discordgo.Session, i *discordgo.Intera
 		h.handleAssignCommand(s, i)
 	case "issues":
 		h.handleIssuesCommand(s, i)
+	case "prs":
+		h.handlePRsCommand(s, i)
+	case "app":
+		h.handleAppCommand(s, i)
+	case "login":
+		h.handleLoginCommand(s, i)
+	case "schedule":
+		h.handleScheduleCommand(s, i)
+	case "webhook":
+		h.handleWebhookCommand(s, i)
+	case "subscribe":
+		h.handleSubscribeCommand(s, i)
+	case "query":
+		h.handleQueryCommand(s, i)
+	case "issue":
+		h.handleIssueCommand(s, i)
 	}
 }
 
@@ -110,6 +213,7 @@ func (h *DiscordHandler) handleSettingCommand(s *discordgo.Session, i *discordgo
 	options := i.ApplicationCommandData().Options
 	action := "token"
 	notificationScope := "all"
+	var renderMode RenderMode
 
 	for _, opt := range options {
 		switch opt.Name {
@@ -117,12 +221,16 @@ func (h *DiscordHandler) handleSettingCommand(s *discordgo.Session, i *discordgo
 			action = opt.StringValue()
 		case "notification_scope":
 			notificationScope = opt.StringValue()
+		case "format":
+			renderMode = RenderMode(opt.StringValue())
 		}
 	}
 
 	switch action {
 	case "token":
 		h.showTokenModal(s, i)
+	case "status":
+		h.handleTokenStatus(s, i)
 	case "notification_channel":
 		switch notificationScope {
 		case "confirm":
@@ -142,13 +250,15 @@ func (h *DiscordHandler) handleSettingCommand(s *discordgo.Session, i *discordgo
 		h.showExcludeModal(s, i, CommandTypeIssues)
 	case "exclude_assign":
 		h.showExcludeModal(s, i, CommandTypeAssign)
+	case "format":
+		h.handleRenderModeSetting(s, i, renderMode)
 	default:
 		h.respondWithError(s, i, "❌ 未対応のアクションです。")
 	}
 }
 
 func (h *DiscordHandler) handleNotificationChannelSetting(s *discordgo.Session, i *discordgo.InteractionCreate, commandType string) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	ctx, cancel := h.newContext()
 	defer cancel()
 
 	guildID := i.GuildID
@@ -176,7 +286,7 @@ func (h *DiscordHandler) handleNotificationChannelSetting(s *discordgo.Session,
 }
 
 func (h *DiscordHandler) handleNotificationChannelConfirm(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	ctx, cancel := h.newContext()
 	defer cancel()
 
 	guildID := i.GuildID
@@ -210,7 +320,7 @@ func (h *DiscordHandler) handleNotificationChannelConfirm(s *discordgo.Session,
 }
 
 func (h *DiscordHandler) handleNotificationChannelClear(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	ctx, cancel := h.newContext()
 	defer cancel()
 
 	guildID := i.GuildID
@@ -225,7 +335,19 @@ func (h *DiscordHandler) handleNotificationChannelClear(s *discordgo.Session, i
 }
 
 func (h *DiscordHandler) showTokenModal(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	// 登録済みのGitHub Enterprise ServerのURLがあれば初期値として表示する
+	currentBaseURL := ""
+	setting, err := h.settingUsecase.GetUserSetting(ctx, i.GuildID, i.Member.User.ID)
+	if err != nil {
+		fmt.Printf("Error getting user setting: %v\n", err)
+	} else if setting != nil {
+		currentBaseURL = setting.GitHubBaseURL
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseModal,
 		Data: &discordgo.InteractionResponseData{
 			CustomID: ModalIDToken,
@@ -244,6 +366,19 @@ func (h *DiscordHandler) showTokenModal(s *discordgo.Session, i *discordgo.Inter
 						},
 					},
 				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    InputIDBaseURL,
+							Label:       "GitHub API URL (Enterprise Server のみ)",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "https://github.example.com/api/v3 (github.com は空欄)",
+							Required:    false,
+							Value:       currentBaseURL,
+							MaxLength:   255,
+						},
+					},
+				},
 			},
 		},
 	})
@@ -253,7 +388,7 @@ func (h *DiscordHandler) showTokenModal(s *discordgo.Session, i *discordgo.Inter
 }
 
 func (h *DiscordHandler) showExcludeModal(s *discordgo.Session, i *discordgo.InteractionCreate, commandType string) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	ctx, cancel := h.newContext()
 	defer cancel()
 	guildID := i.GuildID
 	userID := i.Member.User.ID
@@ -302,31 +437,55 @@ func (h *DiscordHandler) showExcludeModal(s *discordgo.Session, i *discordgo.Int
 	}
 }
 
+// handleComponent はボタンの操作を処理します。CustomID は "<種類>:<データ>" の形式です。
+func (h *DiscordHandler) handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	kind, data, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+	switch kind {
+	case ComponentIDPage:
+		h.handlePageComponent(s, i, data)
+	case ComponentIDIssue:
+		h.handleIssueComponent(s, i, data)
+	}
+}
+
 func (h *DiscordHandler) handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	switch i.ModalSubmitData().CustomID {
+	customID := i.ModalSubmitData().CustomID
+	switch customID {
 	case ModalIDToken:
 		h.handleTokenModalSubmit(s, i)
 	case ModalIDExcludeIssues:
 		h.handleExcludeModalSubmit(s, i, CommandTypeIssues)
 	case ModalIDExcludeAssign:
 		h.handleExcludeModalSubmit(s, i, CommandTypeAssign)
+	default:
+		if id, ok := strings.CutPrefix(customID, ModalIDPageJump+":"); ok {
+			h.handlePageJumpModalSubmit(s, i, id)
+		} else if fullName, ok := strings.CutPrefix(customID, ModalIDIssueCreate+":"); ok {
+			h.handleIssueCreateModalSubmit(s, i, fullName)
+		}
 	}
 }
 
 func (h *DiscordHandler) handleTokenModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	token := h.getModalInputValue(i, InputIDToken)
+	baseURL := h.getModalInputValue(i, InputIDBaseURL)
 
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	ctx, cancel := h.newContext()
 	defer cancel()
 	guildID := i.GuildID
 	channelID := i.ChannelID
 	userID := i.Member.User.ID
 
-	err := h.settingUsecase.SaveToken(ctx, guildID, channelID, userID, token)
+	err := h.settingUsecase.SaveToken(ctx, guildID, channelID, userID, token, baseURL)
 	if err != nil {
 		var message string
+		var scopesErr *usecase.MissingScopesError
 		if ghErr, ok := err.(*github.GitHubError); ok {
 			message = fmt.Sprintf(MsgTokenValidationFailed, ghErr.Message)
+		} else if errors.As(err, &scopesErr) {
+			message = fmt.Sprintf(MsgTokenMissingScopes, strings.Join(scopesErr.Missing, "`, `"))
+		} else if errors.Is(err, github.ErrInvalidBaseURL) {
+			message = MsgInvalidBaseURL
 		} else {
 			message = MsgTokenSaveFailed
 		}
@@ -353,7 +512,7 @@ func (h *DiscordHandler) handleTokenModalSubmit(s *discordgo.Session, i *discord
 func (h *DiscordHandler) handleExcludeModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, commandType string) {
 	excludeText := h.getModalInputValue(i, InputIDExclude)
 
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	ctx, cancel := h.newContext()
 	defer cancel()
 	guildID := i.GuildID
 	channelID := i.ChannelID
@@ -415,9 +574,31 @@ func (h *DiscordHandler) formatIssuesFetchError(err error) string {
 	if err == usecase.ErrTokenNotFound {
 		return MsgTokenNotFound
 	}
+	if errors.Is(err, usecase.ErrPersonalTokenRequired) {
+		return MsgPersonalTokenRequired
+	}
+	if errors.Is(err, usecase.ErrLoginExpired) {
+		return MsgLoginExpired
+	}
+	if errors.Is(err, usecase.ErrTokenExpired) {
+		return MsgTokenExpired
+	}
+	if errors.Is(err, usecase.ErrTokenRevoked) {
+		return MsgTokenRevoked
+	}
 	if ghErr, ok := err.(*github.GitHubError); ok {
 		return fmt.Sprintf(MsgGitHubAPIError, ghErr.Message)
 	}
+	if errors.Is(err, github.ErrPageBudgetExceeded) {
+		return MsgPageBudgetExceeded
+	}
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return fmt.Sprintf(MsgRateLimitExceeded, rateLimitErr.ResetAt.Format("15:04:05"))
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return MsgIssueFetchTimeout
+	}
 	return MsgIssueFetchFailed
 }
 
@@ -471,27 +652,6 @@ func (h *DiscordHandler) respondDeferred(s *discordgo.Session, i *discordgo.Inte
 	})
 }
 
-// sendEmbedsToChannel は指定されたチャンネルにembedsを送信します
-func (h *DiscordHandler) sendEmbedsToChannel(s *discordgo.Session, channelID string, content string, embeds []*discordgo.MessageEmbed) {
-	// Discordの制限: 1メッセージあたり最大10 embeds
-	for i := 0; i < len(embeds); i += MaxEmbedsPerMessage {
-		end := i + MaxEmbedsPerMessage
-		if end > len(embeds) {
-			end = len(embeds)
-		}
-
-		messageContent := ""
-		if i == 0 && content != "" {
-			messageContent = content
-		}
-
-		s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-			Content: messageContent,
-			Embeds:  embeds[i:end],
-		})
-	}
-}
-
 // repositoryInputType はリポジトリ入力の種類を表します
 type repositoryInputType int
 
@@ -540,32 +700,59 @@ func parseRepositoryInput(repoInput string) repositoryInput {
 }
 
 // fetchIssuesByRepository はリポジトリ入力に基づいてissuesを取得します
-func (h *DiscordHandler) fetchIssuesByRepository(ctx context.Context, guildID, userID string, input repositoryInput) ([]github.Issue, *github.RateLimitInfo, []usecase.RepositoryError, error) {
+func (h *DiscordHandler) fetchIssuesByRepository(ctx context.Context, guildID, userID string, input repositoryInput, opts usecase.IssuesOptions) (*usecase.IssuesResult, error) {
 	switch input.inputType {
 	case repoInputTypeAll:
-		result, err := h.issuesUsecase.GetAllRepositoriesIssues(ctx, guildID, userID)
-		if err != nil {
-			if result != nil {
-				return nil, result.RateLimit, nil, err
-			}
-			return nil, nil, nil, err
-		}
-		return result.Issues, result.RateLimit, result.FailedRepos, nil
+		return h.issuesUsecase.GetAllRepositoriesIssues(ctx, guildID, userID, opts)
 	case repoInputTypeUser:
-		result, err := h.issuesUsecase.GetUserIssues(ctx, guildID, userID, input.username)
-		if err != nil {
-			if result != nil {
-				return nil, result.RateLimit, nil, err
-			}
-			return nil, nil, nil, err
-		}
-		return result.Issues, result.RateLimit, result.FailedRepos, nil
+		return h.issuesUsecase.GetUserIssues(ctx, guildID, userID, input.username, opts)
 	case repoInputTypeSpecific:
-		issues, rateLimit, err := h.issuesUsecase.GetRepositoryIssues(ctx, guildID, userID, input.owner, input.repo)
-		return issues, rateLimit, nil, err
+		return h.issuesUsecase.GetRepositoryIssues(ctx, guildID, userID, input.owner, input.repo, opts)
 	default:
-		return nil, nil, nil, fmt.Errorf("unexpected repository input type: %d", input.inputType)
+		return nil, fmt.Errorf("unexpected repository input type: %d", input.inputType)
+	}
+}
+
+// formatRateLimitWarning は Rate Limit の残り回数の警告文を生成します
+func formatRateLimitWarning(rateLimit *github.RateLimitInfo) string {
+	if rateLimit.Limit <= 0 {
+		return fmt.Sprintf(MsgRateLimitWarning, rateLimit.Remaining, rateLimit.ResetAt.Format("15:04:05"))
+	}
+	resource := rateLimit.Resource
+	if resource == "" {
+		resource = github.RateLimitResourceCore
+	}
+	return fmt.Sprintf(MsgRateLimitWarningDetail,
+		rateLimit.Remaining,
+		rateLimit.Limit,
+		resource,
+		rateLimit.ResetAt.Format("15:04:05"))
+}
+
+// buildResultContent は Rate Limit 警告・途中打ち切り・失敗リポジトリをまとめた本文を生成します
+func buildResultContent(result *usecase.IssuesResult) string {
+	var sections []string
+
+	if result.RateLimit != nil && result.RateLimit.Remaining < RateLimitWarningThreshold {
+		sections = append(sections, formatRateLimitWarning(result.RateLimit))
+	}
+
+	if result.Truncated {
+		sections = append(sections, MsgResultsTruncated)
+	}
+
+	// Add failed repositories warning if any
+	if len(result.FailedRepos) > 0 {
+		failedRepoNames := make([]string, 0, len(result.FailedRepos))
+		for _, failedRepo := range result.FailedRepos {
+			failedRepoNames = append(failedRepoNames, failedRepo.RepositoryName)
+		}
+		sections = append(sections, fmt.Sprintf("⚠️ 以下のリポジトリでエラーが発生しました (%d件):\n- %s",
+			len(result.FailedRepos),
+			strings.Join(failedRepoNames, "\n- ")))
 	}
+
+	return strings.Join(sections, "\n\n")
 }
 
 func (h *DiscordHandler) handleIssuesCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -590,7 +777,18 @@ func (h *DiscordHandler) handleIssuesCommand(s *discordgo.Session, i *discordgo.
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	opts, err := usecase.NewIssuesOptions(parseQueryFilter(options), time.Now())
+	if err != nil {
+		h.respondWithError(s, i, MsgInvalidSince)
+		return
+	}
+
+	h.runIssuesCommand(s, i, input, opts)
+}
+
+// runIssuesCommand は /issues と同じ取得を行い、結果を /issues 用の通知チャンネルに送信します
+func (h *DiscordHandler) runIssuesCommand(s *discordgo.Session, i *discordgo.InteractionCreate, input repositoryInput, opts usecase.IssuesOptions) {
+	ctx, cancel := h.newContext()
 	defer cancel()
 	currentChannelID := i.ChannelID
 
@@ -598,53 +796,29 @@ func (h *DiscordHandler) handleIssuesCommand(s *discordgo.Session, i *discordgo.
 	h.respondDeferred(s, i)
 
 	// Get notification channel for issues command
-	notificationChannelID, _, err := h.getNotificationChannelForCommand(ctx, s, i, "issues")
+	notificationChannelID, setting, err := h.getNotificationChannelForCommand(ctx, s, i, "issues")
 	if err != nil {
 		return
 	}
 
 	// Fetch issues based on repository input
-	issues, rateLimit, failedRepos, err := h.fetchIssuesByRepository(ctx, i.GuildID, i.Member.User.ID, input)
+	progressCtx, stopProgress := h.withProgress(ctx, s, i)
+	result, err := h.fetchIssuesByRepository(progressCtx, i.GuildID, i.Member.User.ID, input, opts)
+	stopProgress()
 
 	if err != nil {
 		h.respondEditWithError(s, i, h.formatIssuesFetchError(err))
 		return
 	}
 
-	if len(issues) == 0 {
+	if len(result.Issues) == 0 {
 		h.respondEditWithError(s, i, MsgNoIssuesFound)
 		return
 	}
 
-	embeds := make([]*discordgo.MessageEmbed, 0, len(issues))
-	for _, issue := range issues {
-		embed := createIssueEmbed(issue)
-		embeds = append(embeds, embed)
-	}
+	embeds := renderIssuesResult(parseRenderMode(i.ApplicationCommandData().Options, setting), result)
 
-	var content string
-	if rateLimit != nil && rateLimit.Remaining < RateLimitWarningThreshold {
-		content = fmt.Sprintf(MsgRateLimitWarning,
-			rateLimit.Remaining,
-			rateLimit.ResetAt.Format("15:04:05"))
-	}
-
-	// Add failed repositories warning if any
-	if len(failedRepos) > 0 {
-		failedRepoNames := make([]string, 0, len(failedRepos))
-		for _, failedRepo := range failedRepos {
-			failedRepoNames = append(failedRepoNames, failedRepo.RepositoryName)
-		}
-		failedMsg := fmt.Sprintf("⚠️ 以下のリポジトリでエラーが発生しました (%d件):\n- %s",
-			len(failedRepos),
-			strings.Join(failedRepoNames, "\n- "))
-
-		if len(content) > 0 {
-			content += "\n\n" + failedMsg
-		} else {
-			content = failedMsg
-		}
-	}
+	content := buildResultContent(result)
 
 	// Send completion message to the channel where command was executed
 	completionMsg := "✅ Issue一覧を取得しました。"
@@ -656,45 +830,39 @@ func (h *DiscordHandler) handleIssuesCommand(s *discordgo.Session, i *discordgo.
 	})
 
 	// Send issues to notification channel
-	h.sendEmbedsToChannel(s, notificationChannelID, content, embeds)
+	h.sendEmbedsToChannel(s, notificationChannelID, i.Member.User.ID, content, issueListItems(result.Issues), embeds)
 }
 
 func (h *DiscordHandler) handleAssignCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout)
+	ctx, cancel := h.newContext()
 	defer cancel()
 	currentChannelID := i.ChannelID
 
 	h.respondDeferred(s, i)
 
 	// Get notification channel for assign command
-	notificationChannelID, _, err := h.getNotificationChannelForCommand(ctx, s, i, "assign")
+	notificationChannelID, setting, err := h.getNotificationChannelForCommand(ctx, s, i, "assign")
 	if err != nil {
 		return
 	}
 
-	issues, rateLimit, err := h.issuesUsecase.GetAssignedIssues(ctx, i.GuildID, i.Member.User.ID)
+	opts := usecase.IssuesOptions{Type: parseItemType(i.ApplicationCommandData().Options)}
+	progressCtx, stopProgress := h.withProgress(ctx, s, i)
+	result, err := h.issuesUsecase.GetAssignedIssues(progressCtx, i.GuildID, i.Member.User.ID, opts)
+	stopProgress()
 	if err != nil {
 		h.respondEditWithError(s, i, h.formatIssuesFetchError(err))
 		return
 	}
 
-	if len(issues) == 0 {
+	if len(result.Issues) == 0 {
 		h.respondEditWithError(s, i, MsgNoAssignedIssuesFound)
 		return
 	}
 
-	embeds := make([]*discordgo.MessageEmbed, 0, len(issues))
-	for _, issue := range issues {
-		embed := createIssueEmbed(issue)
-		embeds = append(embeds, embed)
-	}
+	embeds := renderIssuesResult(parseRenderMode(i.ApplicationCommandData().Options, setting), result)
 
-	var content string
-	if rateLimit != nil && rateLimit.Remaining < RateLimitWarningThreshold {
-		content = fmt.Sprintf(MsgRateLimitWarning,
-			rateLimit.Remaining,
-			rateLimit.ResetAt.Format("15:04:05"))
-	}
+	content := buildResultContent(result)
 
 	// Send completion message to the channel where command was executed
 	completionMsg := "✅ 割り当てられたIssue一覧を取得しました。"
@@ -706,7 +874,7 @@ func (h *DiscordHandler) handleAssignCommand(s *discordgo.Session, i *discordgo.
 	})
 
 	// Send issues to notification channel
-	h.sendEmbedsToChannel(s, notificationChannelID, content, embeds)
+	h.sendEmbedsToChannel(s, notificationChannelID, i.Member.User.ID, content, issueListItems(result.Issues), embeds)
 }
 
 // getNotificationChannelForCommand はユーザー設定を取得し、指定されたコマンドタイプの通知チャンネルIDを返します。
@@ -827,16 +995,51 @@ func createIssueEmbed(issue github.Issue) *discordgo.MessageEmbed {
 		})
 	}
 
+	if issue.Milestone != nil {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Milestone",
+			Value:  issue.Milestone.Title,
+			Inline: true,
+		})
+	}
+
+	if issue.ProjectStatus != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Status",
+			Value:  issue.ProjectStatus,
+			Inline: true,
+		})
+	}
+
+	if len(issue.LinkedPullRequests) > 0 {
+		var linked []string
+		for _, pr := range issue.LinkedPullRequests {
+			linked = append(linked, fmt.Sprintf("[#%d](%s)", pr.Number, pr.URL))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Linked PRs",
+			Value:  strings.Join(linked, ", "),
+			Inline: true,
+		})
+	}
+
 	fields = append(fields, &discordgo.MessageEmbedField{
 		Name:   "Updated",
 		Value:  issue.UpdatedAt.Format(time.RFC3339),
 		Inline: true,
 	})
 
+	icon := IconIssue
+	color := ColorGitHubSuccess
+	if issue.IsPullRequest() {
+		icon = IconPullRequest
+		color = ColorGitHubPullRequest
+	}
+
 	return &discordgo.MessageEmbed{
-		Title:  fmt.Sprintf("#%d %s", issue.Number, issue.Title),
+		Title:  fmt.Sprintf("%s #%d %s", icon, issue.Number, issue.Title),
 		URL:    issue.HTMLURL,
-		Color:  ColorGitHubSuccess,
+		Color:  color,
 		Fields: fields,
 	}
 }
@@ -0,0 +1,450 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Issue の操作（CustomID は "issue:<操作>" または "issue:<操作>:<owner>/<repo>#<番号>"）
+const (
+	issueActionSelect      = "select"      // 一覧のセレクトメニューで Issue を選ぶ
+	issueActionClose       = "close"       // クローズ
+	issueActionReopen      = "reopen"      // 再オープン
+	issueActionAssign      = "assign"      // 自分に割り当て
+	issueActionAddLabel    = "addlabel"    // ラベルを追加
+	issueActionRemoveLabel = "removelabel" // ラベルを外す
+)
+
+// セレクトメニューの上限
+const (
+	maxSelectOptions  = 25
+	maxSelectText     = 100
+	maxIssueRefLength = 80 // CustomID (最大100文字) に操作名と一緒に収まる Issue の参照の長さ
+)
+
+// listItem は一覧に表示した Issue・Pull Request です
+type listItem struct {
+	ref   usecase.IssueRef // リポジトリが不明な場合は Owner・Repo が空
+	title string
+	url   string
+}
+
+// issueListItems は Issue の一覧を listItem に変換します
+func issueListItems(issues []github.Issue) []listItem {
+	items := make([]listItem, 0, len(issues))
+	for _, issue := range issues {
+		var owner, repo string
+		if issue.Repository != nil {
+			owner, repo, _ = strings.Cut(issue.Repository.FullName, "/")
+		}
+		items = append(items, listItem{
+			ref:   usecase.IssueRef{Owner: owner, Repo: repo, Number: issue.Number},
+			title: issue.Title,
+			url:   issue.HTMLURL,
+		})
+	}
+	return items
+}
+
+// pullRequestListItems は Pull Request の一覧を listItem に変換します
+func pullRequestListItems(pulls []usecase.PullRequestDetail) []listItem {
+	items := make([]listItem, 0, len(pulls))
+	for _, pull := range pulls {
+		owner, repo, _ := strings.Cut(pull.RepositoryFullName(), "/")
+		items = append(items, listItem{
+			ref:   usecase.IssueRef{Owner: owner, Repo: repo, Number: pull.Number},
+			title: pull.Title,
+			url:   pull.HTMLURL,
+		})
+	}
+	return items
+}
+
+// pageItems は embeds に表示されている項目を、一覧の順に最大 maxSelectOptions 件返します
+func pageItems(embeds []*discordgo.MessageEmbed, items []listItem) []listItem {
+	var result []listItem
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if len(result) >= maxSelectOptions {
+			break
+		}
+		if item.ref.Owner == "" || item.ref.Repo == "" || item.url == "" || seen[item.url] || len(formatIssueRef(item.ref)) > maxIssueRefLength {
+			continue
+		}
+		for _, embed := range embeds {
+			// rich 形式は Embed の URL、compact 形式は本文のリンクに Issue の URL が含まれる
+			if embed.URL == item.url || strings.Contains(embed.Description, "]("+item.url+")") {
+				seen[item.url] = true
+				result = append(result, item)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// formatIssueRef は Issue の参照を "owner/repo#123" 形式に変換します
+func formatIssueRef(ref usecase.IssueRef) string {
+	return fmt.Sprintf("%s/%s#%d", ref.Owner, ref.Repo, ref.Number)
+}
+
+// parseIssueRef は "owner/repo#123" 形式の Issue の参照を解析します
+func parseIssueRef(value string) (usecase.IssueRef, bool) {
+	fullName, numberStr, ok := strings.Cut(value, "#")
+	if !ok {
+		return usecase.IssueRef{}, false
+	}
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" {
+		return usecase.IssueRef{}, false
+	}
+	number, err := strconv.Atoi(numberStr)
+	if err != nil || number <= 0 {
+		return usecase.IssueRef{}, false
+	}
+	return usecase.IssueRef{Owner: owner, Repo: repo, Number: number}, true
+}
+
+func issueComponentID(action string, ref usecase.IssueRef) string {
+	return ComponentIDIssue + ":" + action + ":" + formatIssueRef(ref)
+}
+
+// issueSelectRow は一覧の Issue を選んで操作パネルを開くセレクトメニューです。項目がない場合は nil を返します。
+func issueSelectRow(items []listItem) discordgo.MessageComponent {
+	if len(items) == 0 {
+		return nil
+	}
+	options := make([]discordgo.SelectMenuOption, 0, len(items))
+	for _, item := range items {
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       truncateRunes(fmt.Sprintf("#%d %s", item.ref.Number, item.title), maxSelectText),
+			Value:       formatIssueRef(item.ref),
+			Description: truncateRunes(item.ref.Owner+"/"+item.ref.Repo, maxSelectText),
+		})
+	}
+	return discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:    discordgo.StringSelectMenu,
+				CustomID:    ComponentIDIssue + ":" + issueActionSelect,
+				Placeholder: "🛠️ 操作する Issue を選択",
+				Options:     options,
+			},
+		},
+	}
+}
+
+// issueActionComponents は Issue の操作パネルのボタン・セレクトメニューを返します
+func issueActionComponents(actions *usecase.IssueActions, ref usecase.IssueRef) []discordgo.MessageComponent {
+	issue := actions.Issue
+
+	stateButton := discordgo.Button{
+		Label:    "✅ クローズ",
+		Style:    discordgo.DangerButton,
+		CustomID: issueComponentID(issueActionClose, ref),
+	}
+	if issue.State == github.IssueStateValueClosed {
+		stateButton = discordgo.Button{
+			Label:    "🔄 再オープン",
+			Style:    discordgo.SuccessButton,
+			CustomID: issueComponentID(issueActionReopen, ref),
+		}
+	}
+	buttons := []discordgo.MessageComponent{
+		stateButton,
+		discordgo.Button{
+			Label:    "👤 自分に割り当て",
+			Style:    discordgo.PrimaryButton,
+			CustomID: issueComponentID(issueActionAssign, ref),
+		},
+	}
+	if issue.HTMLURL != "" {
+		buttons = append(buttons, discordgo.Button{
+			Label: "GitHub で開く",
+			Style: discordgo.LinkButton,
+			URL:   issue.HTMLURL,
+		})
+	}
+	components := []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+
+	// 追加できるラベル（リポジトリのラベルのうち、Issue に付いていないもの）
+	var addOptions []discordgo.SelectMenuOption
+	for _, label := range actions.Labels {
+		if len(addOptions) >= maxSelectOptions {
+			break
+		}
+		if issue.HasLabel(label.Name) || len(label.Name) > maxSelectText {
+			continue
+		}
+		addOptions = append(addOptions, discordgo.SelectMenuOption{Label: label.Name, Value: label.Name})
+	}
+	if len(addOptions) > 0 {
+		components = append(components, labelSelectRow(issueComponentID(issueActionAddLabel, ref), "🏷️ ラベルを追加", addOptions))
+	}
+
+	var removeOptions []discordgo.SelectMenuOption
+	for _, label := range issue.Labels {
+		if len(removeOptions) >= maxSelectOptions {
+			break
+		}
+		if len(label.Name) > maxSelectText {
+			continue
+		}
+		removeOptions = append(removeOptions, discordgo.SelectMenuOption{Label: label.Name, Value: label.Name})
+	}
+	if len(removeOptions) > 0 {
+		components = append(components, labelSelectRow(issueComponentID(issueActionRemoveLabel, ref), "🧹 ラベルを外す", removeOptions))
+	}
+	return components
+}
+
+func labelSelectRow(customID, placeholder string, options []discordgo.SelectMenuOption) discordgo.MessageComponent {
+	return discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:    discordgo.StringSelectMenu,
+				CustomID:    customID,
+				Placeholder: placeholder,
+				Options:     options,
+			},
+		},
+	}
+}
+
+// handleIssueComponent は Issue の操作パネルの操作を処理します。data は CustomID の "issue:" 以降です。
+// 操作はボタンを押したユーザー自身のトークンで行うため、一覧を表示したユーザー以外も操作できます。
+func (h *DiscordHandler) handleIssueComponent(s *discordgo.Session, i *discordgo.InteractionCreate, data string) {
+	action, refValue, _ := strings.Cut(data, ":")
+	values := i.MessageComponentData().Values
+
+	if action == issueActionSelect {
+		if len(values) == 0 {
+			return
+		}
+		ref, ok := parseIssueRef(values[0])
+		if !ok {
+			h.respondWithError(s, i, MsgIssueActionInvalid)
+			return
+		}
+		h.showIssueActions(s, i, ref)
+		return
+	}
+
+	ref, ok := parseIssueRef(refValue)
+	if !ok {
+		h.respondWithError(s, i, MsgIssueActionInvalid)
+		return
+	}
+
+	// 操作パネル（エフェメラルメッセージ）をその場で更新する
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+	guildID := i.GuildID
+	userID := i.Member.User.ID
+	issueRef := formatIssueRef(ref)
+
+	var (
+		actions *usecase.IssueActions
+		err     error
+		message string
+	)
+	switch action {
+	case issueActionClose:
+		actions, err = h.issuesUsecase.CloseIssue(ctx, guildID, userID, ref)
+		message = fmt.Sprintf(MsgIssueClosed, issueRef)
+	case issueActionReopen:
+		actions, err = h.issuesUsecase.ReopenIssue(ctx, guildID, userID, ref)
+		message = fmt.Sprintf(MsgIssueReopened, issueRef)
+	case issueActionAssign:
+		actions, err = h.issuesUsecase.AssignIssueToMe(ctx, guildID, userID, ref)
+		message = fmt.Sprintf(MsgIssueAssignedToMe, issueRef)
+	case issueActionAddLabel, issueActionRemoveLabel:
+		if len(values) == 0 {
+			return
+		}
+		if action == issueActionAddLabel {
+			actions, err = h.issuesUsecase.AddIssueLabel(ctx, guildID, userID, ref, values[0])
+			message = fmt.Sprintf(MsgIssueLabelAdded, values[0], issueRef)
+		} else {
+			actions, err = h.issuesUsecase.RemoveIssueLabel(ctx, guildID, userID, ref, values[0])
+			message = fmt.Sprintf(MsgIssueLabelRemoved, values[0], issueRef)
+		}
+	default:
+		return
+	}
+	if err != nil {
+		h.respondEditWithError(s, i, h.formatIssuesFetchError(err))
+		return
+	}
+
+	h.editIssueActions(s, i, message, actions, ref)
+	h.refreshListMessage(s, i, *actions.Issue)
+}
+
+// showIssueActions は選択された Issue の操作パネルを、選択したユーザーだけに表示します
+func (h *DiscordHandler) showIssueActions(s *discordgo.Session, i *discordgo.InteractionCreate, ref usecase.IssueRef) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	actions, err := h.issuesUsecase.GetIssueActions(ctx, i.GuildID, i.Member.User.ID, ref)
+	if err != nil {
+		h.respondEditWithError(s, i, h.formatIssuesFetchError(err))
+		return
+	}
+	h.editIssueActions(s, i, "", actions, ref)
+}
+
+// editIssueActions は操作パネルの本文・Embed・操作を最新の Issue の状態に更新します
+func (h *DiscordHandler) editIssueActions(s *discordgo.Session, i *discordgo.InteractionCreate, message string, actions *usecase.IssueActions, ref usecase.IssueRef) {
+	embeds := []*discordgo.MessageEmbed{createIssueEmbed(*actions.Issue)}
+	components := issueActionComponents(actions, ref)
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &message,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+}
+
+// refreshListMessage は操作パネルを開いた一覧のメッセージのうち、操作した Issue の表示を最新の状態に更新します。
+// 一覧のメッセージは操作パネルの interaction_metadata (選択したメッセージの ID) で特定し、
+// ページ送りがある場合は保存しているすべてのページも更新します。
+func (h *DiscordHandler) refreshListMessage(s *discordgo.Session, i *discordgo.InteractionCreate, issue github.Issue) {
+	if i.Message == nil || i.Message.InteractionMetadata == nil || i.Message.InteractionMetadata.InteractedMessageID == "" {
+		return
+	}
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	listMessage, err := s.ChannelMessage(i.ChannelID, i.Message.InteractionMetadata.InteractedMessageID, discordgo.WithContext(ctx))
+	if err != nil {
+		log.Printf("Failed to fetch list message %s: %v", i.Message.InteractionMetadata.InteractedMessageID, err)
+		return
+	}
+	edit := &discordgo.MessageEdit{ID: listMessage.ID, Channel: listMessage.ChannelID}
+
+	update := func(embed *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+		return refreshListEmbed(embed, issue)
+	}
+	id := pageSessionID(listMessage.Components)
+	if session, ok := h.pages.updateEmbeds(id, update); ok {
+		content, embeds, components := renderPage(id, session)
+		edit.Content, edit.Embeds, edit.Components = &content, &embeds, &components
+	} else {
+		// ページ送りがない（または期限切れの）一覧は表示中の embeds だけを更新する
+		embeds := make([]*discordgo.MessageEmbed, 0, len(listMessage.Embeds))
+		changed := false
+		for _, embed := range listMessage.Embeds {
+			updated := update(embed)
+			changed = changed || updated != embed
+			embeds = append(embeds, updated)
+		}
+		if !changed {
+			return
+		}
+		edit.Embeds = &embeds
+	}
+	if _, err := s.ChannelMessageEditComplex(edit, discordgo.WithContext(ctx)); err != nil {
+		log.Printf("Failed to update list message %s: %v", listMessage.ID, err)
+	}
+}
+
+// refreshListEmbed は一覧の Embed に issue が表示されている場合、状態・ラベル・担当者・更新日時を更新した Embed を返します。
+// 表示されていない場合は embed をそのまま返します。
+// rich 形式は項目だけを書き換え、GraphQL でのみ取得できる項目 (Status・Linked PRs など) は一覧の表示を残します。
+func refreshListEmbed(embed *discordgo.MessageEmbed, issue github.Issue) *discordgo.MessageEmbed {
+	if issue.HTMLURL == "" {
+		return embed
+	}
+	if embed.URL == issue.HTMLURL {
+		updated := *embed
+		updated.Fields = append([]*discordgo.MessageEmbedField(nil), embed.Fields...)
+		isIssueEmbed := embedFieldIndex(&updated, "State") >= 0
+		if isIssueEmbed {
+			setEmbedField(&updated, "State", issue.State)
+		}
+		var labels, assignees []string
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		for _, assignee := range issue.Assignees {
+			assignees = append(assignees, assignee.Login)
+		}
+		setEmbedField(&updated, "Labels", strings.Join(labels, ", "), "Assignees", "Milestone", "Status", "Linked PRs", "Updated")
+		if isIssueEmbed {
+			setEmbedField(&updated, "Assignees", strings.Join(assignees, ", "), "Milestone", "Status", "Linked PRs", "Updated")
+		}
+		setEmbedField(&updated, "Updated", issue.UpdatedAt.Format(time.RFC3339))
+		return &updated
+	}
+
+	// compact 形式は Issue の行のラベル・担当者を書き換える（Pull Request の行はレビュー・CI の表示のため変更しない）
+	link := "](" + issue.HTMLURL + ")"
+	if issue.IsPullRequest() || !strings.Contains(embed.Description, link) {
+		return embed
+	}
+	lines := strings.Split(embed.Description, "\n")
+	for idx, line := range lines {
+		end := strings.Index(line, link)
+		if end < 0 {
+			continue
+		}
+		parts := append([]string{line[:end+len(link)]}, compactIssueDetails(issue)...)
+		lines[idx] = truncateRunes(strings.Join(parts, " · "), MaxEmbedDescriptionLength)
+	}
+	updated := *embed
+	updated.Description = truncateRunes(strings.Join(lines, "\n"), MaxEmbedDescriptionLength)
+	return &updated
+}
+
+func embedFieldIndex(embed *discordgo.MessageEmbed, name string) int {
+	for idx, field := range embed.Fields {
+		if field.Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// setEmbedField は name の項目の値を value に変更します。value が空の場合は項目を削除します。
+// 項目がない場合は before のいずれかの項目の前（見つからない場合は末尾）に追加します。
+func setEmbedField(embed *discordgo.MessageEmbed, name, value string, before ...string) {
+	idx := embedFieldIndex(embed, name)
+	switch {
+	case value == "" && idx >= 0:
+		embed.Fields = append(embed.Fields[:idx:idx], embed.Fields[idx+1:]...)
+	case value == "":
+	case idx >= 0:
+		field := *embed.Fields[idx]
+		field.Value = value
+		embed.Fields[idx] = &field
+	default:
+		insertAt := len(embed.Fields)
+		for fieldIdx, field := range embed.Fields {
+			if slices.Contains(before, field.Name) {
+				insertAt = fieldIdx
+				break
+			}
+		}
+		embed.Fields = slices.Insert(embed.Fields, insertAt, &discordgo.MessageEmbedField{Name: name, Value: value, Inline: true})
+	}
+}
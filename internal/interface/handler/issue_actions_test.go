@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestRefreshListEmbed(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 19, 8, 40, 0, 0, time.UTC)
+	issue := github.Issue{
+		Number:     42,
+		Title:      "Crash when the config file is empty",
+		HTMLURL:    "https://github.com/acme/api/issues/42",
+		State:      github.IssueStateValueClosed,
+		UpdatedAt:  updatedAt,
+		Labels:     []github.Label{{Name: "bug"}, {Name: "p1"}},
+		Assignees:  []github.User{{Login: "octocat"}},
+		Repository: &github.Repository{FullName: "acme/api"},
+	}
+
+	t.Run("rich issue embed keeps GraphQL-only fields", func(t *testing.T) {
+		embed := &discordgo.MessageEmbed{
+			URL: issue.HTMLURL,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "Repository", Value: "acme/api"},
+				{Name: "State", Value: "open"},
+				{Name: "Labels", Value: "triage"},
+				{Name: "Status", Value: "In Progress"},
+				{Name: "Updated", Value: "2024-03-18T09:12:45Z"},
+			},
+		}
+		got := refreshListEmbed(embed, issue)
+
+		want := []string{
+			"Repository=acme/api",
+			"State=closed",
+			"Labels=bug, p1",
+			"Assignees=octocat",
+			"Status=In Progress",
+			"Updated=" + updatedAt.Format(time.RFC3339),
+		}
+		if fields := embedFieldSummary(got); !slices.Equal(fields, want) {
+			t.Errorf("fields = %q, want %q", fields, want)
+		}
+		if embed.Fields[1].Value != "open" || len(embed.Fields) != 5 {
+			t.Errorf("original embed was modified: %q", embedFieldSummary(embed))
+		}
+	})
+
+	t.Run("rich embed removes emptied fields", func(t *testing.T) {
+		embed := &discordgo.MessageEmbed{
+			URL: issue.HTMLURL,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "State", Value: "open"},
+				{Name: "Labels", Value: "bug"},
+				{Name: "Assignees", Value: "octocat"},
+				{Name: "Updated", Value: "2024-03-18T09:12:45Z"},
+			},
+		}
+		unlabeled := issue
+		unlabeled.Labels = nil
+		unlabeled.Assignees = nil
+		got := refreshListEmbed(embed, unlabeled)
+
+		want := []string{"State=closed", "Updated=" + updatedAt.Format(time.RFC3339)}
+		if fields := embedFieldSummary(got); !slices.Equal(fields, want) {
+			t.Errorf("fields = %q, want %q", fields, want)
+		}
+	})
+
+	t.Run("compact line is rewritten", func(t *testing.T) {
+		embed := &discordgo.MessageEmbed{Description: "🟢 [#41 Other](https://github.com/acme/api/issues/41) · bug\n" +
+			"🟢 [#42 Crash when the config file is empty](https://github.com/acme/api/issues/42) · triage"}
+		got := refreshListEmbed(embed, issue)
+
+		want := "🟢 [#41 Other](https://github.com/acme/api/issues/41) · bug\n" +
+			"🟢 [#42 Crash when the config file is empty](https://github.com/acme/api/issues/42) · bug, p1 · @octocat"
+		if got.Description != want {
+			t.Errorf("description = %q, want %q", got.Description, want)
+		}
+	})
+
+	t.Run("other issues are left unchanged", func(t *testing.T) {
+		embed := &discordgo.MessageEmbed{URL: "https://github.com/acme/api/issues/41", Description: "[#41 Other](https://github.com/acme/api/issues/41)"}
+		if got := refreshListEmbed(embed, issue); got != embed {
+			t.Errorf("refreshListEmbed() returned a new embed for another issue: %+v", got)
+		}
+	})
+}
+
+func embedFieldSummary(embed *discordgo.MessageEmbed) []string {
+	var fields []string
+	for _, field := range embed.Fields {
+		fields = append(fields, field.Name+"="+field.Value)
+	}
+	return fields
+}
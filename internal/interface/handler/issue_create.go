@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /issue のサブコマンド
+const (
+	IssueSubcommandCreate = "create"
+)
+
+// Issue 作成のモーダルの入力の上限
+const (
+	maxIssueTitleLength  = 256
+	maxIssueBodyLength   = 4000 // Discord のテキスト入力の上限
+	maxIssueLabelsLength = 400
+)
+
+// issueCommand は /issue コマンドの定義です
+func issueCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "issue",
+		Description: "GitHub の Issue を操作します",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        IssueSubcommandCreate,
+				Description: "モーダルでタイトル・本文・ラベルを入力して Issue を作成します",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "repository",
+						Description: "owner/repo 形式で指定",
+						Required:    true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "template",
+						Description:  ".github/ISSUE_TEMPLATE のテンプレート (Markdown 形式のみ)",
+						Required:     false,
+						Autocomplete: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (h *DiscordHandler) handleIssueCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+	switch options[0].Name {
+	case IssueSubcommandCreate:
+		h.handleIssueCreate(s, i, options[0].Options)
+	}
+}
+
+// parseIssueCreateOptions は /issue create の repository・template を読み取ります
+func parseIssueCreateOptions(options []*discordgo.ApplicationCommandInteractionDataOption) (repositoryName, templateName string) {
+	for _, opt := range options {
+		switch opt.Name {
+		case "repository":
+			repositoryName = strings.TrimSpace(opt.StringValue())
+		case "template":
+			templateName = strings.TrimSpace(opt.StringValue())
+		}
+	}
+	return repositoryName, templateName
+}
+
+// handleIssueCreate は Issue を入力するモーダルを表示します。テンプレートを指定した場合は内容を初期値にします。
+func (h *DiscordHandler) handleIssueCreate(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	repositoryName, templateName := parseIssueCreateOptions(options)
+	input := parseRepositoryInput(repositoryName)
+	customID := ModalIDIssueCreate + ":" + input.owner + "/" + input.repo
+	if input.inputType != repoInputTypeSpecific || len(customID) > 100 {
+		h.respondWithError(s, i, MsgIssueRepositoryRequired)
+		return
+	}
+
+	template := &github.IssueTemplate{}
+	if templateName != "" {
+		// モーダルは最初の応答でしか表示できないため、Discord の応答期限 (3秒) より前に打ち切る
+		ctx, cancel := context.WithTimeout(context.Background(), IssueTemplateTimeout)
+		defer cancel()
+		var err error
+		template, err = h.issuesUsecase.GetIssueTemplate(ctx, i.GuildID, i.Member.User.ID, input.owner, input.repo, templateName)
+		if err != nil {
+			h.respondWithError(s, i, h.formatIssuesFetchError(err))
+			return
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: customID,
+			Title:    truncateRunes("Issue の作成: "+input.owner+"/"+input.repo, 45),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  InputIDIssueTitle,
+							Label:     "タイトル",
+							Style:     discordgo.TextInputShort,
+							Value:     truncateRunes(template.Title, maxIssueTitleLength),
+							Required:  true,
+							MaxLength: maxIssueTitleLength,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  InputIDIssueBody,
+							Label:     "本文 (Markdown)",
+							Style:     discordgo.TextInputParagraph,
+							Value:     truncateRunes(template.Body, maxIssueBodyLength),
+							Required:  false,
+							MaxLength: maxIssueBodyLength,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    InputIDIssueLabels,
+							Label:       "ラベル (カンマ区切り)",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "bug, enhancement",
+							Value:       truncateRunes(strings.Join(template.Labels, ", "), maxIssueLabelsLength),
+							Required:    false,
+							MaxLength:   maxIssueLabelsLength,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Error responding with modal: %v\n", err)
+	}
+}
+
+// handleIssueCreateModalSubmit はモーダルの入力から Issue を作成し、作成した Issue を Embed で投稿します。
+// fullName は CustomID の "issue_create_modal:" 以降の owner/repo です。
+func (h *DiscordHandler) handleIssueCreateModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, fullName string) {
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" {
+		h.respondWithError(s, i, MsgIssueRepositoryRequired)
+		return
+	}
+	title := strings.TrimSpace(h.getModalInputValue(i, InputIDIssueTitle))
+	if title == "" {
+		h.respondWithError(s, i, MsgIssueTitleRequired)
+		return
+	}
+
+	h.respondDeferred(s, i)
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	issue, err := h.issuesUsecase.CreateIssue(ctx, i.GuildID, i.Member.User.ID, owner, repo, github.NewIssue{
+		Title:  title,
+		Body:   strings.TrimSpace(h.getModalInputValue(i, InputIDIssueBody)),
+		Labels: splitCommaList(h.getModalInputValue(i, InputIDIssueLabels)),
+	})
+	if err != nil {
+		h.respondEditWithError(s, i, h.formatIssuesFetchError(err))
+		return
+	}
+
+	// 作成した Issue にも一覧と同じ操作のセレクトメニューを付ける
+	content := fmt.Sprintf(MsgIssueCreated, formatIssueRef(usecase.IssueRef{Owner: owner, Repo: repo, Number: issue.Number}))
+	embeds := []*discordgo.MessageEmbed{createIssueEmbed(*issue)}
+	var components []discordgo.MessageComponent
+	if row := issueSelectRow(pageItems(embeds, issueListItems([]github.Issue{*issue}))); row != nil {
+		components = append(components, row)
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+}
+
+// handleIssueTemplateAutocomplete は /issue create の template に、repository のテンプレートの候補を返します
+func (h *DiscordHandler) handleIssueTemplateAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	repositoryName, typed := parseIssueCreateOptions(options)
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+
+	input := parseRepositoryInput(repositoryName)
+	if input.inputType == repoInputTypeSpecific {
+		ctx, cancel := context.WithTimeout(context.Background(), IssueTemplateTimeout)
+		defer cancel()
+		names, err := h.issuesUsecase.ListIssueTemplates(ctx, i.GuildID, i.Member.User.ID, input.owner, input.repo)
+		if err == nil {
+			typed = strings.ToLower(typed)
+			for _, name := range names {
+				if len(choices) >= maxSelectOptions {
+					break
+				}
+				if len(name) <= maxSelectText && strings.Contains(strings.ToLower(name), typed) {
+					choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+				}
+			}
+		}
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// issueFilterOptions は Issue の絞り込み条件・並び順・まとめ方を指定するオプションです
+func issueFilterOptions() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "state",
+			Description: "Issue の状態 (既定: open)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "open", Value: string(github.IssueStateOpen)},
+				{Name: "closed", Value: string(github.IssueStateClosed)},
+				{Name: "all", Value: string(github.IssueStateAll)},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "labels",
+			Description: "すべて付いている Issue に絞り込むラベル (カンマ区切り)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "exclude_labels",
+			Description: "付いている Issue を除外するラベル (カンマ区切り)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "milestone",
+			Description: "マイルストーンのタイトルまたは番号。* (いずれか) / none (なし)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "assignee",
+			Description: "担当者の GitHub ユーザー名。* (いずれか) / none (なし)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "creator",
+			Description: "作成者の GitHub ユーザー名",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "mentioned",
+			Description: "メンションされた GitHub ユーザー名",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "since",
+			Description: "この日時以降に更新された Issue。例: 2024-04-01, 7d, 24h, 2w",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "sort",
+			Description: "並び順 (既定: API の既定順)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "作成日時", Value: string(github.IssueSortCreated)},
+				{Name: "更新日時", Value: string(github.IssueSortUpdated)},
+				{Name: "コメント数", Value: string(github.IssueSortComments)},
+				{Name: "経過日数 (desc で古い順)", Value: string(github.IssueSortAge)},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "direction",
+			Description: "並び順の方向 (既定: desc)",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "昇順 (asc)", Value: github.SortDirectionAsc},
+				{Name: "降順 (desc)", Value: github.SortDirectionDesc},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "group_by",
+			Description: "結果をまとめて件数付きの見出しを付ける単位",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "リポジトリ", Value: string(usecase.GroupByRepository)},
+				{Name: "ラベル", Value: string(usecase.GroupByLabel)},
+				{Name: "担当者", Value: string(usecase.GroupByAssignee)},
+				{Name: "マイルストーン", Value: string(usecase.GroupByMilestone)},
+			},
+		},
+	}
+}
+
+// parseQueryFilter はコマンドオプションから種類と絞り込み条件を読み取ります。
+// since は入力した文字列のまま返すため、usecase.NewIssuesOptions で検証・変換してください。
+func parseQueryFilter(options []*discordgo.ApplicationCommandInteractionDataOption) entity.QueryFilter {
+	var filter entity.QueryFilter
+	for _, opt := range options {
+		switch opt.Name {
+		case "type":
+			filter.Type = opt.StringValue()
+		case "state":
+			filter.State = opt.StringValue()
+		case "labels":
+			filter.Labels = splitCommaList(opt.StringValue())
+		case "exclude_labels":
+			filter.ExcludeLabels = splitCommaList(opt.StringValue())
+		case "milestone":
+			filter.Milestone = strings.TrimSpace(opt.StringValue())
+		case "assignee":
+			filter.Assignee = trimLogin(opt.StringValue())
+		case "creator":
+			filter.Creator = trimLogin(opt.StringValue())
+		case "mentioned":
+			filter.Mentioned = trimLogin(opt.StringValue())
+		case "since":
+			filter.Since = strings.TrimSpace(opt.StringValue())
+		case "sort":
+			filter.Sort = opt.StringValue()
+		case "direction":
+			filter.Direction = opt.StringValue()
+		case "group_by":
+			filter.GroupBy = opt.StringValue()
+		}
+	}
+	return filter
+}
+
+// formatQueryFilter は絞り込み条件をコマンドのオプションと同じ key:value 形式で表示用に変換します
+func formatQueryFilter(filter entity.QueryFilter) string {
+	var parts []string
+	add := func(key, value string) {
+		if value != "" {
+			parts = append(parts, fmt.Sprintf("%s:%s", key, value))
+		}
+	}
+	add("type", filter.Type)
+	add("state", filter.State)
+	add("labels", strings.Join(filter.Labels, ","))
+	add("exclude_labels", strings.Join(filter.ExcludeLabels, ","))
+	add("milestone", filter.Milestone)
+	add("assignee", filter.Assignee)
+	add("creator", filter.Creator)
+	add("mentioned", filter.Mentioned)
+	add("since", filter.Since)
+	add("sort", filter.Sort)
+	add("direction", filter.Direction)
+	add("group_by", filter.GroupBy)
+	if len(parts) == 0 {
+		return "条件なし"
+	}
+	return "`" + strings.Join(parts, " ") + "`"
+}
+
+// trimLogin は GitHub ユーザー名の前後の空白と先頭の @ を取り除きます
+func trimLogin(value string) string {
+	return strings.TrimPrefix(strings.TrimSpace(value), "@")
+}
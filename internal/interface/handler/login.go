@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// loginCommand は /login コマンドの定義です
+func loginCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "login",
+		Description: "GitHub にログインしてトークンを登録します (トークンの貼り付けは不要です)",
+	}
+}
+
+// handleLoginCommand はデバイス認可フローを開始し、ユーザーコードをエフェメラルメッセージで表示します。
+// 認可の完了はバックグラウンドで待ち、結果で同じメッセージを更新します。
+func (h *DiscordHandler) handleLoginCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	code, err := h.loginUsecase.StartLogin(ctx)
+	if err != nil {
+		h.respondWithError(s, i, formatLoginError(err))
+		return
+	}
+
+	// インタラクションのトークン（15 分）が切れると結果でメッセージを更新できないため、待つのは LoginWaitLimit まで
+	wait := min(time.Duration(code.ExpiresIn)*time.Second, LoginWaitLimit)
+	expiresAt := time.Now().Add(wait)
+	h.respondWithSuccess(s, i, fmt.Sprintf(MsgLoginStarted, code.VerificationURI, code.UserCode, expiresAt.Format("15:04")))
+
+	go h.waitForLogin(s, i, code, wait)
+}
+
+// waitForLogin はユーザーが認可するまで最大 wait 待ち、結果でエフェメラルメッセージを更新します。
+// メッセージを更新できない場合は DM で結果を送ります。
+func (h *DiscordHandler) waitForLogin(s *discordgo.Session, i *discordgo.InteractionCreate, code *github.DeviceCode, wait time.Duration) {
+	// 待つ時間に加えて、トークンの検証・保存の時間を見込む
+	ctx, cancel := context.WithTimeout(h.ctx, wait+DefaultContextTimeout)
+	defer cancel()
+
+	userID := i.Member.User.ID
+	message := MsgLoginCompleted
+	if err := h.loginUsecase.CompleteLogin(ctx, i.GuildID, i.ChannelID, userID, code); err != nil {
+		message = formatLoginError(err)
+	}
+
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &message,
+	})
+	if err == nil {
+		return
+	}
+	log.Printf("Failed to update /login response for user %s: %v", userID, err)
+
+	channel, err := s.UserChannelCreate(userID)
+	if err == nil {
+		_, err = s.ChannelMessageSend(channel.ID, message)
+	}
+	if err != nil {
+		log.Printf("Failed to send /login result to user %s: %v", userID, err)
+	}
+}
+
+// formatLoginError は /login のエラーをメッセージに変換します
+func formatLoginError(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrLoginNotConfigured):
+		return MsgLoginNotConfigured
+	case errors.Is(err, github.ErrDeviceCodeExpired), errors.Is(err, context.DeadlineExceeded):
+		return MsgLoginCodeExpired
+	case errors.Is(err, github.ErrAuthorizationDenied):
+		return MsgLoginDenied
+	}
+	var ghErr *github.GitHubError
+	if errors.As(err, &ghErr) {
+		return fmt.Sprintf(MsgTokenValidationFailed, ghErr.Message)
+	}
+	return MsgLoginFailed
+}
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ページ送りボタンの操作（CustomID は "page:<セッションID>:<操作>"）
+const (
+	pageActionPrev = "prev"
+	pageActionNext = "next"
+	pageActionJump = "jump"
+)
+
+// pageSession は複数ページに分けて表示している一覧の状態です
+type pageSession struct {
+	userID    string // ページを送れるユーザー（コマンドの実行者・定期ダイジェストの登録者）
+	content   string
+	pages     [][]*discordgo.MessageEmbed
+	items     [][]listItem // ページごとの操作できる Issue（セレクトメニューの項目）
+	count     int          // 一覧の件数
+	page      int          // 0 始まりの表示中のページ
+	expiresAt time.Time
+}
+
+// pageCount はページ数を返します
+func (p pageSession) pageCount() int {
+	return len(p.pages)
+}
+
+// pageStore はページ送りの状態をメモリ上に保持します。
+// 最後の操作から ttl が経過した状態は破棄します（Bot の再起動でも失われます）。
+// 保持する状態は maxSessions 件までで、超える場合は最後の操作が古いものから破棄します。
+type pageStore struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	maxSessions int
+	sessions    map[string]*pageSession
+}
+
+func newPageStore(ttl time.Duration, maxSessions int) *pageStore {
+	return &pageStore{
+		ttl:         ttl,
+		maxSessions: maxSessions,
+		sessions:    make(map[string]*pageSession),
+	}
+}
+
+// add は状態を保存し、ボタンの CustomID に使うセッションIDを返します。
+// 期限切れの状態と、上限を超える古い状態もここで破棄します。
+func (p *pageStore) add(session *pageSession) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for id, existing := range p.sessions {
+		if now.After(existing.expiresAt) {
+			delete(p.sessions, id)
+		}
+	}
+	for len(p.sessions) >= max(p.maxSessions, 1) {
+		p.evictOldest()
+	}
+
+	id := rand.Text()
+	session.expiresAt = now.Add(p.ttl)
+	p.sessions[id] = session
+	return id
+}
+
+// evictOldest は有効期限が最も早い（最後の操作が最も古い）状態を破棄します。p.mu を保持して呼び出します。
+func (p *pageStore) evictOldest() {
+	var oldestID string
+	var oldest time.Time
+	for id, session := range p.sessions {
+		if oldestID == "" || session.expiresAt.Before(oldest) {
+			oldestID, oldest = id, session.expiresAt
+		}
+	}
+	delete(p.sessions, oldestID)
+}
+
+// get はセッションIDの状態を返します。存在しない・期限切れの場合は false を返します。
+func (p *pageStore) get(id string) (pageSession, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, ok := p.sessions[id]
+	if !ok || time.Now().After(session.expiresAt) {
+		return pageSession{}, false
+	}
+	return *session, true
+}
+
+// setPage は表示中のページを page（範囲外の場合は先頭・末尾）に変更し、有効期限を延長します。
+// 存在しない・期限切れの場合は false を返します。
+func (p *pageStore) setPage(id string, page int) (pageSession, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, ok := p.sessions[id]
+	now := time.Now()
+	if !ok || now.After(session.expiresAt) {
+		return pageSession{}, false
+	}
+	session.page = min(max(page, 0), session.pageCount()-1)
+	session.expiresAt = now.Add(p.ttl)
+	return *session, true
+}
+
+// updateEmbeds はすべてのページの embeds を update の戻り値に置き換え、更新後の状態を返します。
+// 存在しない・期限切れの場合は false を返します。
+func (p *pageStore) updateEmbeds(id string, update func(*discordgo.MessageEmbed) *discordgo.MessageEmbed) (pageSession, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, ok := p.sessions[id]
+	if !ok || time.Now().After(session.expiresAt) {
+		return pageSession{}, false
+	}
+	pages := make([][]*discordgo.MessageEmbed, 0, len(session.pages))
+	for _, page := range session.pages {
+		updated := make([]*discordgo.MessageEmbed, 0, len(page))
+		for _, embed := range page {
+			updated = append(updated, update(embed))
+		}
+		pages = append(pages, updated)
+	}
+	session.pages = pages
+	return *session, true
+}
+
+// pageSessionID はメッセージのページ送りボタンの CustomID からセッションIDを返します（ページ送りがない場合は空文字列）
+func pageSessionID(components []discordgo.MessageComponent) string {
+	for _, component := range components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, child := range row.Components {
+			if button, ok := child.(*discordgo.Button); ok {
+				if data, found := strings.CutPrefix(button.CustomID, ComponentIDPage+":"); found {
+					id, _, _ := strings.Cut(data, ":")
+					return id
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// sendEmbedsToChannel は指定されたチャンネルに items の一覧の embeds を送信します。
+// Discordの制限 (1メッセージあたり最大10 embeds・合計6000文字) を超える場合は1つのメッセージにまとめ、
+// userID のユーザーだけが操作できるページ送りボタンを付けます。
+// 表示中の Issue を選んで操作パネルを開くセレクトメニューも付けます（誰でも選択できます）。
+func (h *DiscordHandler) sendEmbedsToChannel(s *discordgo.Session, channelID, userID, content string, items []listItem, embeds []*discordgo.MessageEmbed) {
+	pages := splitEmbedPages(embeds)
+	if len(pages) <= 1 {
+		var components []discordgo.MessageComponent
+		if row := issueSelectRow(pageItems(embeds, items)); row != nil {
+			components = append(components, row)
+		}
+		s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:         content,
+			Embeds:          embeds,
+			Components:      components,
+			AllowedMentions: userOnlyMentions(userID),
+		})
+		return
+	}
+
+	pageItemList := make([][]listItem, 0, len(pages))
+	for _, page := range pages {
+		pageItemList = append(pageItemList, pageItems(page, items))
+	}
+	session := &pageSession{userID: userID, content: content, pages: pages, items: pageItemList, count: len(items)}
+	id := h.pages.add(session)
+	pageContent, pageEmbeds, components := renderPage(id, *session)
+	s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         pageContent,
+		Embeds:          pageEmbeds,
+		Components:      components,
+		AllowedMentions: userOnlyMentions(userID),
+	})
+}
+
+// userOnlyMentions は userID のユーザーへのメンションだけを通知する設定を返します（userID が空の場合は通知しない）
+func userOnlyMentions(userID string) *discordgo.MessageAllowedMentions {
+	mentions := &discordgo.MessageAllowedMentions{}
+	if userID != "" {
+		mentions.Users = []string{userID}
+	}
+	return mentions
+}
+
+// renderPage は表示中のページの本文・embeds・ページ送りボタン・Issue のセレクトメニューを返します
+func renderPage(id string, session pageSession) (string, []*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	pageCount := session.pageCount()
+	content := fmt.Sprintf(MsgPageIndicator, session.page+1, pageCount, session.count)
+	if session.content != "" {
+		content = session.content + "\n" + content
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ 前へ",
+					Style:    discordgo.SecondaryButton,
+					CustomID: pageComponentID(id, pageActionPrev),
+					Disabled: session.page == 0,
+				},
+				discordgo.Button{
+					Label:    "次へ ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: pageComponentID(id, pageActionNext),
+					Disabled: session.page == pageCount-1,
+				},
+				discordgo.Button{
+					Label:    "🔢 ページ指定",
+					Style:    discordgo.SecondaryButton,
+					CustomID: pageComponentID(id, pageActionJump),
+				},
+			},
+		},
+	}
+	if row := issueSelectRow(session.items[session.page]); row != nil {
+		components = append(components, row)
+	}
+	return content, session.pages[session.page], components
+}
+
+func pageComponentID(id, action string) string {
+	return ComponentIDPage + ":" + id + ":" + action
+}
+
+// handlePageComponent はページ送りボタンの操作を処理します。data は CustomID の "page:" 以降です。
+func (h *DiscordHandler) handlePageComponent(s *discordgo.Session, i *discordgo.InteractionCreate, data string) {
+	id, action, _ := strings.Cut(data, ":")
+	session, ok := h.pages.get(id)
+	if !ok {
+		h.respondWithError(s, i, MsgPageExpired)
+		return
+	}
+	if session.userID != i.Member.User.ID {
+		h.respondWithError(s, i, MsgPageNotOwner)
+		return
+	}
+
+	switch action {
+	case pageActionPrev:
+		h.showPage(s, i, id, session.page-1)
+	case pageActionNext:
+		h.showPage(s, i, id, session.page+1)
+	case pageActionJump:
+		h.showPageJumpModal(s, i, id, session)
+	}
+}
+
+// showPageJumpModal はページ番号を入力するモーダルを表示します
+func (h *DiscordHandler) showPageJumpModal(s *discordgo.Session, i *discordgo.InteractionCreate, id string, session pageSession) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: ModalIDPageJump + ":" + id,
+			Title:    "ページ指定",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    InputIDPage,
+							Label:       fmt.Sprintf("ページ番号 (1〜%d)", session.pageCount()),
+							Style:       discordgo.TextInputShort,
+							Placeholder: strconv.Itoa(session.page + 1),
+							Required:    true,
+							MaxLength:   4,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Error responding with modal: %v\n", err)
+	}
+}
+
+// handlePageJumpModalSubmit はページ番号の入力を受け取り、そのページを表示します
+func (h *DiscordHandler) handlePageJumpModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, id string) {
+	session, ok := h.pages.get(id)
+	if !ok {
+		h.respondWithError(s, i, MsgPageExpired)
+		return
+	}
+	if session.userID != i.Member.User.ID {
+		h.respondWithError(s, i, MsgPageNotOwner)
+		return
+	}
+
+	page, err := strconv.Atoi(strings.TrimSpace(h.getModalInputValue(i, InputIDPage)))
+	if err != nil || page < 1 || page > session.pageCount() {
+		h.respondWithError(s, i, fmt.Sprintf(MsgPageInvalid, session.pageCount()))
+		return
+	}
+	h.showPage(s, i, id, page-1)
+}
+
+// showPage は表示中のページを変更し、ボタンが付いたメッセージを更新します
+func (h *DiscordHandler) showPage(s *discordgo.Session, i *discordgo.InteractionCreate, id string, page int) {
+	session, ok := h.pages.setPage(id, page)
+	if !ok {
+		h.respondWithError(s, i, MsgPageExpired)
+		return
+	}
+
+	content, embeds, components := renderPage(id, session)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Embeds:     embeds,
+			Components: components,
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestPageStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := newPageStore(time.Hour, 2)
+	newSession := func() *pageSession {
+		return &pageSession{pages: make([][]*discordgo.MessageEmbed, 2), items: make([][]listItem, 2)}
+	}
+
+	first := store.add(newSession())
+	second := store.add(newSession())
+	// 最初の一覧のページを送ると、2 つ目の一覧の方が最後の操作が古くなる
+	store.sessions[first].expiresAt = store.sessions[second].expiresAt.Add(time.Minute)
+	third := store.add(newSession())
+
+	if len(store.sessions) != 2 {
+		t.Errorf("kept %d sessions, want 2", len(store.sessions))
+	}
+	if _, ok := store.get(second); ok {
+		t.Error("least recently used session was not evicted")
+	}
+	for _, id := range []string{first, third} {
+		if _, ok := store.get(id); !ok {
+			t.Errorf("session %s was evicted", id)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// progressUpdateInterval は取得中メッセージを更新する最小間隔です（Discord の Rate Limit 対策）
+const progressUpdateInterval = 2 * time.Second
+
+// progressReporter はページ取得の進捗を集計し、遅延応答のメッセージに表示します
+type progressReporter struct {
+	s *discordgo.Session
+	i *discordgo.InteractionCreate
+
+	mu          sync.Mutex
+	fetched     int
+	lastPages   map[string]int // ページングの対象ごとの最終ページ番号
+	lastUpdated time.Time
+	stopped     bool
+
+	// sending は送信中のメッセージの更新です。stop は送信の完了を待ち、結果の表示が進捗で上書きされないようにします。
+	sending sync.WaitGroup
+}
+
+// withProgress は ctx にページ取得の進捗を表示する設定を追加します。
+// 返された stop を呼び出した後は、応答メッセージを更新しません。
+func (h *DiscordHandler) withProgress(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) (context.Context, func()) {
+	reporter := &progressReporter{
+		s:           s,
+		i:           i,
+		lastPages:   make(map[string]int),
+		lastUpdated: time.Now(),
+	}
+	return github.WithPageProgress(ctx, reporter.report), reporter.stop
+}
+
+// report は進捗を集計し、前回の更新から progressUpdateInterval 以上経過していればメッセージを更新します。
+// 並行して取得しているページの集計を待たせないよう、メッセージの送信はロックを外してから行います。
+func (r *progressReporter) report(progress github.PageProgress) {
+	message, ok := r.record(progress)
+	if !ok {
+		return
+	}
+	defer r.sending.Done()
+	r.s.InteractionResponseEdit(r.i.Interaction, &discordgo.WebhookEdit{
+		Content: &message,
+	})
+}
+
+// record は進捗を集計し、メッセージを更新する場合は表示する本文と true を返します（呼び出し側で sending.Done を呼び出します）
+func (r *progressReporter) record(progress github.PageProgress) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fetched++
+	if progress.LastPage > 0 {
+		r.lastPages[progress.Request] = progress.LastPage
+	}
+
+	if r.stopped || time.Since(r.lastUpdated) < progressUpdateInterval {
+		return "", false
+	}
+	r.lastUpdated = time.Now()
+
+	total := 0
+	for _, lastPage := range r.lastPages {
+		total += lastPage
+	}
+	message := fmt.Sprintf(MsgFetchProgress, r.fetched)
+	if total >= r.fetched {
+		message = fmt.Sprintf(MsgFetchProgressWithTotal, r.fetched, total)
+	}
+	r.sending.Add(1)
+	return message, true
+}
+
+func (r *progressReporter) stop() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+	r.sending.Wait()
+}
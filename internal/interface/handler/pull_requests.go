@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /prs の取得モード
+const (
+	PRModeOpen            = "open"
+	PRModeReviewRequested = "review_requested"
+)
+
+// pullRequestsCommand は /prs コマンドの定義です
+func pullRequestsCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "prs",
+		Description: "オープンな Pull Request をレビュー状況・CI 状態とともに取得します",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "repository",
+				Description: "owner/repo・owner・all のいずれか (mode:open で必須)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "取得モード (既定: open)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "オープンな PR", Value: PRModeOpen},
+					{Name: "自分へのレビュー依頼 (review-requested:me)", Value: PRModeReviewRequested},
+				},
+			},
+			formatOption(),
+		},
+	}
+}
+
+// fetchPullRequests は取得モードとリポジトリ入力に基づいて Pull Request を取得します
+func (h *DiscordHandler) fetchPullRequests(ctx context.Context, guildID, userID, mode string, input repositoryInput) (*usecase.PullRequestsResult, error) {
+	if mode == PRModeReviewRequested {
+		return h.pullRequestsUsecase.GetReviewRequestedPullRequests(ctx, guildID, userID)
+	}
+
+	switch input.inputType {
+	case repoInputTypeAll:
+		return h.pullRequestsUsecase.GetAllRepositoriesPullRequests(ctx, guildID, userID)
+	case repoInputTypeUser:
+		return h.pullRequestsUsecase.GetUserPullRequests(ctx, guildID, userID, input.username)
+	case repoInputTypeSpecific:
+		return h.pullRequestsUsecase.GetRepositoryPullRequests(ctx, guildID, userID, input.owner, input.repo)
+	default:
+		return nil, fmt.Errorf("unexpected repository input type: %d", input.inputType)
+	}
+}
+
+func (h *DiscordHandler) handlePRsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	repoInput := ""
+	mode := PRModeOpen
+
+	for _, opt := range options {
+		switch opt.Name {
+		case "repository":
+			repoInput = opt.StringValue()
+		case "mode":
+			mode = opt.StringValue()
+		}
+	}
+
+	var input repositoryInput
+	if mode != PRModeReviewRequested {
+		if repoInput == "" {
+			h.respondWithError(s, i, MsgInvalidRepoFormat)
+			return
+		}
+		input = parseRepositoryInput(repoInput)
+		if input.inputType == repoInputTypeInvalid {
+			h.respondWithError(s, i, MsgInvalidRepoFormat)
+			return
+		}
+	}
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+	currentChannelID := i.ChannelID
+
+	h.respondDeferred(s, i)
+
+	// Pull Request は /issues と同じ通知チャンネルに送信する
+	notificationChannelID, setting, err := h.getNotificationChannelForCommand(ctx, s, i, "issues")
+	if err != nil {
+		return
+	}
+
+	progressCtx, stopProgress := h.withProgress(ctx, s, i)
+	result, err := h.fetchPullRequests(progressCtx, i.GuildID, i.Member.User.ID, mode, input)
+	stopProgress()
+	if err != nil {
+		h.respondEditWithError(s, i, h.formatIssuesFetchError(err))
+		return
+	}
+
+	if len(result.PullRequests) == 0 {
+		h.respondEditWithError(s, i, MsgNoPullRequestsFound)
+		return
+	}
+
+	embeds := newRenderer(parseRenderMode(options, setting), len(result.PullRequests)).renderPullRequests(result.PullRequests)
+
+	content := buildResultContent(&usecase.IssuesResult{
+		RateLimit:   result.RateLimit,
+		FailedRepos: result.FailedRepos,
+		Truncated:   result.Truncated,
+	})
+
+	completionMsg := "✅ Pull Request 一覧を取得しました。"
+	if currentChannelID != notificationChannelID {
+		completionMsg = fmt.Sprintf("✅ Pull Request 一覧を取得しました。結果は <#%s> に送信されました。", notificationChannelID)
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &completionMsg,
+	})
+
+	h.sendEmbedsToChannel(s, notificationChannelID, i.Member.User.ID, content, pullRequestListItems(result.PullRequests), embeds)
+}
+
+// formatReviewDecision はレビュー状況を表示用の文字列に変換します
+func formatReviewDecision(decision string) string {
+	switch decision {
+	case usecase.ReviewDecisionApproved:
+		return "✅ Approved"
+	case usecase.ReviewDecisionChangesRequested:
+		return "❌ Changes requested"
+	default:
+		return "👀 Review required"
+	}
+}
+
+// formatCIStatus は CI 状態を表示用の文字列に変換します
+func formatCIStatus(status string) string {
+	switch status {
+	case usecase.CIStatusSuccess:
+		return "🟢 Success"
+	case usecase.CIStatusFailure:
+		return "🔴 Failure"
+	case usecase.CIStatusPending:
+		return "🟡 Pending"
+	default:
+		return "⚪ None"
+	}
+}
+
+func createPullRequestEmbed(pull usecase.PullRequestDetail) *discordgo.MessageEmbed {
+	var reviewers []string
+	for _, reviewer := range pull.RequestedReviewers {
+		reviewers = append(reviewers, reviewer.Login)
+	}
+	for _, team := range pull.RequestedTeams {
+		reviewers = append(reviewers, "@"+team.Slug)
+	}
+
+	var labels []string
+	for _, label := range pull.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Repository",
+			Value:  pull.RepositoryFullName(),
+			Inline: true,
+		},
+		{
+			Name:   "Author",
+			Value:  pull.User.Login,
+			Inline: true,
+		},
+		{
+			Name:   "Review",
+			Value:  formatReviewDecision(pull.ReviewDecision),
+			Inline: true,
+		},
+		{
+			Name:   "CI",
+			Value:  formatCIStatus(pull.CIStatus),
+			Inline: true,
+		},
+	}
+
+	if len(reviewers) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Requested Reviewers",
+			Value:  strings.Join(reviewers, ", "),
+			Inline: true,
+		})
+	}
+
+	if len(labels) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Labels",
+			Value:  strings.Join(labels, ", "),
+			Inline: true,
+		})
+	}
+
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   "Updated",
+		Value:  pull.UpdatedAt.Format(time.RFC3339),
+		Inline: true,
+	})
+
+	title := fmt.Sprintf("%s #%d %s", IconPullRequest, pull.Number, pull.Title)
+	color := ColorGitHubPullRequest
+	if pull.Draft {
+		title = fmt.Sprintf("%s [Draft] #%d %s", IconDraftPullRequest, pull.Number, pull.Title)
+		color = ColorGitHubDraft
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:  title,
+		URL:    pull.HTMLURL,
+		Color:  color,
+		Fields: fields,
+	}
+}
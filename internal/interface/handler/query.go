@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /query の操作
+const (
+	QueryActionSave   = "save"
+	QueryActionRun    = "run"
+	QueryActionList   = "list"
+	QueryActionDelete = "delete"
+)
+
+// queryCommand は /query コマンドの定義です。絞り込みのオプションは /issues と同じです。
+func queryCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "query",
+		Description: "/issues の対象と絞り込み条件に名前を付けて保存・実行します",
+		Options: append([]*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "実行する操作",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "保存", Value: QueryActionSave},
+					{Name: "実行", Value: QueryActionRun},
+					{Name: "一覧", Value: QueryActionList},
+					{Name: "削除", Value: QueryActionDelete},
+				},
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "name",
+				Description:  fmt.Sprintf("条件の名前 (空白を含まない %d 文字以内)", usecase.MaxQueryNameLength),
+				Required:     false,
+				Autocomplete: true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "repository",
+				Description: "action:save の対象 (owner/repo・owner・all)",
+				Required:    false,
+			},
+			itemTypeOption(),
+			formatOption(),
+		}, issueFilterOptions()...),
+	}
+}
+
+func (h *DiscordHandler) handleQueryCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	action := QueryActionList
+	var name, repositoryName string
+	for _, opt := range options {
+		switch opt.Name {
+		case "action":
+			action = opt.StringValue()
+		case "name":
+			name = strings.TrimSpace(opt.StringValue())
+		case "repository":
+			repositoryName = strings.TrimSpace(opt.StringValue())
+		}
+	}
+
+	if action != QueryActionList && name == "" {
+		h.respondWithError(s, i, MsgQueryNameRequired)
+		return
+	}
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+	guildID := i.GuildID
+	userID := i.Member.User.ID
+
+	switch action {
+	case QueryActionSave:
+		if parseRepositoryInput(repositoryName).inputType == repoInputTypeInvalid {
+			h.respondWithError(s, i, MsgInvalidRepoFormat)
+			return
+		}
+		savedQuery, created, err := h.queryUsecase.Save(ctx, guildID, userID, name, repositoryName, parseQueryFilter(options))
+		if err != nil {
+			h.respondWithError(s, i, formatQueryError(err, name))
+			return
+		}
+		message := MsgQueryUpdated
+		if created {
+			message = MsgQuerySaved
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(message, savedQuery.Name, savedQuery.Repository, formatQueryFilter(savedQuery.Filter)))
+	case QueryActionRun:
+		savedQuery, err := h.queryUsecase.Get(ctx, guildID, userID, name)
+		if err != nil {
+			h.respondWithError(s, i, formatQueryError(err, name))
+			return
+		}
+		input := parseRepositoryInput(savedQuery.Repository)
+		if input.inputType == repoInputTypeInvalid {
+			h.respondWithError(s, i, MsgInvalidRepoFormat)
+			return
+		}
+		opts, err := usecase.NewIssuesOptions(savedQuery.Filter, time.Now())
+		if err != nil {
+			h.respondWithError(s, i, formatQueryError(err, name))
+			return
+		}
+		h.runIssuesCommand(s, i, input, opts)
+	case QueryActionDelete:
+		// 条件を使う定期ダイジェストも削除されるため、件数を案内する
+		digests, err := h.scheduleUsecase.List(ctx, guildID, userID)
+		if err != nil {
+			h.respondWithError(s, i, MsgScheduleListFailed)
+			return
+		}
+		if err := h.queryUsecase.Delete(ctx, guildID, userID, name); err != nil {
+			h.respondWithError(s, i, formatQueryError(err, name))
+			return
+		}
+		removed := 0
+		for _, digest := range digests {
+			if digest.Scope == usecase.ScheduleScopeQuery && digest.QueryName == name {
+				removed++
+			}
+		}
+		if removed > 0 {
+			h.respondWithSuccess(s, i, fmt.Sprintf(MsgQueryDeletedDigests, name, removed))
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgQueryDeleted, name))
+	default:
+		queries, err := h.queryUsecase.List(ctx, guildID, userID)
+		if err != nil {
+			h.respondWithError(s, i, MsgQueryListFailed)
+			return
+		}
+		if len(queries) == 0 {
+			h.respondWithSuccess(s, i, MsgNoQueries)
+			return
+		}
+		lines := []string{MsgQueryList}
+		for _, savedQuery := range queries {
+			lines = append(lines, fmt.Sprintf("- **%s** — %s %s", savedQuery.Name, savedQuery.Repository, formatQueryFilter(savedQuery.Filter)))
+		}
+		h.respondWithSuccess(s, i, splitMessageLines(lines, MaxMessageLength)[0])
+	}
+}
+
+// handleAutocomplete は保存した条件の名前（/query の name・/schedule の query）と、/issue create のテンプレートの候補を返します
+func (h *DiscordHandler) handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if data.Name == "issue" && len(data.Options) > 0 {
+		h.handleIssueTemplateAutocomplete(s, i, data.Options[0].Options)
+		return
+	}
+	var focused *discordgo.ApplicationCommandInteractionDataOption
+	for _, opt := range data.Options {
+		if opt.Focused {
+			focused = opt
+		}
+	}
+	if focused == nil || !isQueryNameOption(data.Name, focused.Name) {
+		return
+	}
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	names, err := h.queryUsecase.Complete(ctx, i.GuildID, i.Member.User.ID, focused.StringValue())
+	if err == nil {
+		for _, name := range names {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+		}
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+}
+
+// isQueryNameOption はオプションが保存した条件の名前を入力するものかを返します
+func isQueryNameOption(commandName, optionName string) bool {
+	return (commandName == "query" && optionName == "name") || (commandName == "schedule" && optionName == "query")
+}
+
+// formatQueryError は /query のエラーをメッセージに変換します
+func formatQueryError(err error, name string) string {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidQueryName):
+		return fmt.Sprintf(MsgQueryInvalidName, usecase.MaxQueryNameLength)
+	case errors.Is(err, usecase.ErrQueryRepository):
+		return MsgInvalidRepoFormat
+	case errors.Is(err, usecase.ErrInvalidSince):
+		return MsgInvalidSince
+	case errors.Is(err, usecase.ErrQueryLimitReached):
+		return fmt.Sprintf(MsgQueryLimitReached, usecase.MaxSavedQueriesPerUser)
+	case errors.Is(err, usecase.ErrQueryNotFound):
+		return fmt.Sprintf(MsgQueryNotFound, name)
+	default:
+		return MsgQuerySaveFailed
+	}
+}
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RenderMode は一覧の表示形式です
+type RenderMode string
+
+const (
+	RenderModeRich    RenderMode = "rich"    // 1件ごとに Embed を作成する（既定）
+	RenderModeCompact RenderMode = "compact" // 1件1行にまとめた Embed を作成する
+	RenderModeAuto    RenderMode = "auto"    // 1メッセージに収まる件数は rich、超える場合は compact
+)
+
+// compact 形式の1行・1 Embed の上限
+const (
+	compactTitleLength   = 80
+	compactLinesPerEmbed = 20
+)
+
+// listRenderer は Issue・Pull Request の一覧を Embed に変換します
+type listRenderer interface {
+	renderIssues(issues []github.Issue) []*discordgo.MessageEmbed
+	// renderIssueGroup は見出し header を付けて Issue のグループを変換します
+	renderIssueGroup(header string, issues []github.Issue) []*discordgo.MessageEmbed
+	renderPullRequests(pulls []usecase.PullRequestDetail) []*discordgo.MessageEmbed
+}
+
+// formatOption は表示形式を選ぶ共通オプションです
+func formatOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "format",
+		Description: "表示形式 (既定: /setting action:format の設定)",
+		Required:    false,
+		Choices: []*discordgo.ApplicationCommandOptionChoice{
+			{Name: "rich (1件ずつ Embed)", Value: string(RenderModeRich)},
+			{Name: "compact (1件1行の一覧)", Value: string(RenderModeCompact)},
+			{Name: "auto (10件を超えたら compact)", Value: string(RenderModeAuto)},
+		},
+	}
+}
+
+// settingFormatOption は /setting action:format で既定の表示形式を選ぶオプションです
+func settingFormatOption() *discordgo.ApplicationCommandOption {
+	option := formatOption()
+	option.Description = "action:format で設定する一覧の既定の表示形式 (未指定の場合は現在の設定を表示)"
+	return option
+}
+
+// parseRenderMode はコマンドの format オプション、未指定の場合はユーザーの既定の表示形式を返します
+func parseRenderMode(options []*discordgo.ApplicationCommandInteractionDataOption, setting *entity.UserSetting) RenderMode {
+	for _, opt := range options {
+		if opt.Name == "format" {
+			return RenderMode(opt.StringValue())
+		}
+	}
+	if setting != nil && setting.RenderMode != "" {
+		return RenderMode(setting.RenderMode)
+	}
+	return RenderModeRich
+}
+
+// newRenderer は表示形式と件数に応じた listRenderer を返します
+func newRenderer(mode RenderMode, count int) listRenderer {
+	switch mode {
+	case RenderModeCompact:
+		return compactRenderer{}
+	case RenderModeAuto:
+		if count > MaxEmbedsPerMessage {
+			return compactRenderer{}
+		}
+	}
+	return richRenderer{}
+}
+
+// renderIssuesResult は Issue の取得結果を Embed に変換します。グループにまとめた結果はグループごとに見出しを付けます。
+func renderIssuesResult(mode RenderMode, result *usecase.IssuesResult) []*discordgo.MessageEmbed {
+	renderer := newRenderer(mode, len(result.Issues))
+	if result.Groups == nil {
+		return renderer.renderIssues(result.Issues)
+	}
+	var embeds []*discordgo.MessageEmbed
+	for _, group := range result.Groups {
+		embeds = append(embeds, renderer.renderIssueGroup(formatGroupHeader(group), group.Issues)...)
+	}
+	return embeds
+}
+
+// formatGroupHeader はグループの見出し（アイコン・名前・件数）を返します
+func formatGroupHeader(group usecase.IssueGroup) string {
+	icon, name := "📁", group.Name
+	switch group.By {
+	case usecase.GroupByRepository:
+		if name == "" {
+			name = "リポジトリ不明"
+		}
+	case usecase.GroupByLabel:
+		icon = "🏷️"
+		if name == "" {
+			name = "ラベルなし"
+		}
+	case usecase.GroupByAssignee:
+		icon = "👤"
+		if name == "" {
+			name = "担当者なし"
+		}
+	case usecase.GroupByMilestone:
+		icon = "🎯"
+		if name == "" {
+			name = "マイルストーンなし"
+		}
+	}
+	return truncateRunes(fmt.Sprintf(MsgGroupHeader, icon, name, len(group.Issues)), 256)
+}
+
+// richRenderer は1件ごとに詳細な Embed を作成します
+type richRenderer struct{}
+
+func (richRenderer) renderIssues(issues []github.Issue) []*discordgo.MessageEmbed {
+	embeds := make([]*discordgo.MessageEmbed, 0, len(issues))
+	for _, issue := range issues {
+		embeds = append(embeds, createIssueEmbed(issue))
+	}
+	return embeds
+}
+
+func (r richRenderer) renderIssueGroup(header string, issues []github.Issue) []*discordgo.MessageEmbed {
+	return append([]*discordgo.MessageEmbed{{Title: header, Color: ColorGitHubDraft}}, r.renderIssues(issues)...)
+}
+
+func (richRenderer) renderPullRequests(pulls []usecase.PullRequestDetail) []*discordgo.MessageEmbed {
+	embeds := make([]*discordgo.MessageEmbed, 0, len(pulls))
+	for _, pull := range pulls {
+		embeds = append(embeds, createPullRequestEmbed(pull))
+	}
+	return embeds
+}
+
+// compactRenderer は `[#123 title](url) · labels · @assignee` の1行ずつを Embed の本文にまとめます
+type compactRenderer struct{}
+
+func (compactRenderer) renderIssues(issues []github.Issue) []*discordgo.MessageEmbed {
+	repositories := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Repository != nil {
+			repositories[issue.Repository.FullName] = true
+		}
+	}
+	// 複数のリポジトリの結果はリポジトリ名を付けて区別する
+	withRepository := len(repositories) > 1
+
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		icon := IconIssue
+		if issue.IsPullRequest() {
+			icon = IconPullRequest
+		}
+		repoName := ""
+		if withRepository && issue.Repository != nil {
+			repoName = issue.Repository.FullName
+		}
+
+		parts := []string{fmt.Sprintf("%s %s", icon, formatCompactLink(repoName, issue.Number, issue.Title, issue.HTMLURL))}
+		lines = append(lines, strings.Join(append(parts, compactIssueDetails(issue)...), " · "))
+	}
+	return compactEmbeds(lines, ColorGitHubSuccess)
+}
+
+// compactIssueDetails は compact 形式の1行のうち、リンクに続くラベル・担当者の部分を返します
+func compactIssueDetails(issue github.Issue) []string {
+	var parts []string
+	var labels []string
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	if len(labels) > 0 {
+		parts = append(parts, strings.Join(labels, ", "))
+	}
+	var assignees []string
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, "@"+assignee.Login)
+	}
+	if len(assignees) > 0 {
+		parts = append(parts, strings.Join(assignees, " "))
+	}
+	return parts
+}
+
+// renderIssueGroup は見出しを Embed のタイトルにします。複数の Embed に分かれる場合は2つ目以降に「(続き)」を付けます。
+func (r compactRenderer) renderIssueGroup(header string, issues []github.Issue) []*discordgo.MessageEmbed {
+	embeds := r.renderIssues(issues)
+	for idx, embed := range embeds {
+		embed.Title = header
+		if idx > 0 {
+			embed.Title += " (続き)"
+		}
+	}
+	return embeds
+}
+
+func (compactRenderer) renderPullRequests(pulls []usecase.PullRequestDetail) []*discordgo.MessageEmbed {
+	repositories := make(map[string]bool)
+	for _, pull := range pulls {
+		repositories[pull.RepositoryFullName()] = true
+	}
+	withRepository := len(repositories) > 1
+
+	lines := make([]string, 0, len(pulls))
+	for _, pull := range pulls {
+		icon := IconPullRequest
+		if pull.Draft {
+			icon = IconDraftPullRequest
+		}
+		repoName := ""
+		if withRepository {
+			repoName = pull.RepositoryFullName()
+		}
+		lines = append(lines, strings.Join([]string{
+			fmt.Sprintf("%s %s", icon, formatCompactLink(repoName, pull.Number, pull.Title, pull.HTMLURL)),
+			formatReviewDecision(pull.ReviewDecision),
+			formatCIStatus(pull.CIStatus),
+			"@" + pull.User.Login,
+		}, " · "))
+	}
+	return compactEmbeds(lines, ColorGitHubPullRequest)
+}
+
+// formatCompactLink は `[owner/repo#123 title](url)` 形式のリンクを返します（repoName が空の場合は `[#123 title](url)`）
+func formatCompactLink(repoName string, number int, title, url string) string {
+	title = strings.NewReplacer("[", "\\[", "]", "\\]").Replace(truncateRunes(title, compactTitleLength))
+	return fmt.Sprintf("[%s#%d %s](%s)", repoName, number, title, url)
+}
+
+// compactEmbeds は行を compactLinesPerEmbed 行・Embed 本文の上限文字数ごとに分けた Embed を返します
+func compactEmbeds(lines []string, color int) []*discordgo.MessageEmbed {
+	var embeds []*discordgo.MessageEmbed
+	var chunk []string
+	length := 0
+	flush := func() {
+		if len(chunk) > 0 {
+			embeds = append(embeds, &discordgo.MessageEmbed{
+				Description: strings.Join(chunk, "\n"),
+				Color:       color,
+			})
+		}
+		chunk, length = nil, 0
+	}
+	for _, line := range lines {
+		line = truncateRunes(line, MaxEmbedDescriptionLength)
+		if len(chunk) >= compactLinesPerEmbed || length+len([]rune(line))+1 > MaxEmbedDescriptionLength {
+			flush()
+		}
+		chunk = append(chunk, line)
+		length += len([]rune(line)) + 1
+	}
+	flush()
+	return embeds
+}
+
+// embedLength は Discord の1メッセージあたりの文字数制限の対象となる Embed の文字数を返します
+func embedLength(embed *discordgo.MessageEmbed) int {
+	length := len([]rune(embed.Title)) + len([]rune(embed.Description))
+	for _, field := range embed.Fields {
+		length += len([]rune(field.Name)) + len([]rune(field.Value))
+	}
+	if embed.Footer != nil {
+		length += len([]rune(embed.Footer.Text))
+	}
+	if embed.Author != nil {
+		length += len([]rune(embed.Author.Name))
+	}
+	return length
+}
+
+// splitEmbedPages は embeds を Discord の制限（1メッセージあたり最大10 embeds・合計6000文字）に収まるページに分けます
+func splitEmbedPages(embeds []*discordgo.MessageEmbed) [][]*discordgo.MessageEmbed {
+	var pages [][]*discordgo.MessageEmbed
+	var page []*discordgo.MessageEmbed
+	length := 0
+	for _, embed := range embeds {
+		embedLen := embedLength(embed)
+		if len(page) >= MaxEmbedsPerMessage || (len(page) > 0 && length+embedLen > MaxEmbedCharsPerMessage) {
+			pages = append(pages, page)
+			page, length = nil, 0
+		}
+		page = append(page, embed)
+		length += embedLen
+	}
+	if len(page) > 0 {
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// formatRenderMode は表示形式を表示用の文字列に変換します
+func formatRenderMode(mode RenderMode) string {
+	switch mode {
+	case RenderModeCompact:
+		return "compact (1件1行の一覧)"
+	case RenderModeAuto:
+		return "auto (10件を超えたら compact)"
+	default:
+		return "rich (1件ずつ Embed)"
+	}
+}
+
+// handleRenderModeSetting は一覧の既定の表示形式を保存します。mode が空の場合は現在の設定を表示します。
+func (h *DiscordHandler) handleRenderModeSetting(s *discordgo.Session, i *discordgo.InteractionCreate, mode RenderMode) {
+	ctx, cancel := h.newContext()
+	defer cancel()
+	guildID := i.GuildID
+	userID := i.Member.User.ID
+
+	if mode == "" {
+		setting, err := h.settingUsecase.GetUserSetting(ctx, guildID, userID)
+		if err != nil {
+			h.respondWithError(s, i, MsgRenderModeLoadFailed)
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgRenderModeStatus, formatRenderMode(parseRenderMode(nil, setting))))
+		return
+	}
+
+	if err := h.settingUsecase.SaveRenderMode(ctx, guildID, i.ChannelID, userID, string(mode)); err != nil {
+		h.respondWithError(s, i, MsgRenderModeSaveFailed)
+		return
+	}
+	h.respondWithSuccess(s, i, fmt.Sprintf(MsgRenderModeSaved, formatRenderMode(mode)))
+}
@@ -0,0 +1,389 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /schedule の操作
+const (
+	ScheduleActionAdd    = "add"
+	ScheduleActionList   = "list"
+	ScheduleActionDelete = "delete"
+)
+
+// scheduleCommand は /schedule コマンドの定義です
+func scheduleCommand() *discordgo.ApplicationCommand {
+	minID := float64(1)
+	return &discordgo.ApplicationCommand{
+		Name:        "schedule",
+		Description: "Issue の定期ダイジェストを通知チャンネルに投稿します",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "実行する操作",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "追加", Value: ScheduleActionAdd},
+					{Name: "一覧", Value: ScheduleActionList},
+					{Name: "削除", Value: ScheduleActionDelete},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "cron",
+				Description: "実行時刻の cron 式 (分 時 日 月 曜日)。例: 0 9 * * mon-fri",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "scope",
+				Description: "投稿する内容 (既定: assign)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "/assign (自分の担当 Issue)", Value: usecase.ScheduleScopeAssign},
+					{Name: "/issues (リポジトリの Issue)", Value: usecase.ScheduleScopeIssues},
+					{Name: "/query (保存した条件)", Value: usecase.ScheduleScopeQuery},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "投稿する Issue (既定: full)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "full (毎回すべて)", Value: usecase.ScheduleModeFull},
+					{Name: "changes (新規・クローズ・割り当て・ラベル変更だけ)", Value: usecase.ScheduleModeChanges},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "repository",
+				Description: "scope:issues の対象 (owner/repo・owner・all)",
+				Required:    false,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "query",
+				Description:  "scope:query で使う保存した条件の名前",
+				Required:     false,
+				Autocomplete: true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "timezone",
+				Description: "cron 式のタイムゾーン (既定: " + usecase.DefaultScheduleTimezone + ")。例: UTC, America/New_York",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "id",
+				Description: "削除する定期ダイジェストの ID (action:delete で必須)",
+				Required:    false,
+				MinValue:    &minID,
+			},
+		},
+	}
+}
+
+func (h *DiscordHandler) handleScheduleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	action := ScheduleActionList
+	scope := usecase.ScheduleScopeAssign
+	var cronExpr, repositoryName, queryName, mode, timezone string
+	var id int64
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "action":
+			action = opt.StringValue()
+		case "cron":
+			cronExpr = strings.TrimSpace(opt.StringValue())
+		case "scope":
+			scope = opt.StringValue()
+		case "mode":
+			mode = opt.StringValue()
+		case "repository":
+			repositoryName = strings.TrimSpace(opt.StringValue())
+		case "query":
+			queryName = strings.TrimSpace(opt.StringValue())
+		case "timezone":
+			timezone = strings.TrimSpace(opt.StringValue())
+		case "id":
+			id = opt.IntValue()
+		}
+	}
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+	guildID := i.GuildID
+	userID := i.Member.User.ID
+
+	switch action {
+	case ScheduleActionAdd:
+		if cronExpr == "" {
+			h.respondWithError(s, i, MsgScheduleCronRequired)
+			return
+		}
+		if scope == usecase.ScheduleScopeIssues && parseRepositoryInput(repositoryName).inputType == repoInputTypeInvalid {
+			h.respondWithError(s, i, MsgInvalidRepoFormat)
+			return
+		}
+		var queryID int64
+		if scope == usecase.ScheduleScopeQuery {
+			if queryName == "" {
+				h.respondWithError(s, i, MsgScheduleQueryRequired)
+				return
+			}
+			savedQuery, err := h.queryUsecase.Get(ctx, guildID, userID, queryName)
+			if err != nil {
+				h.respondWithError(s, i, formatQueryError(err, queryName))
+				return
+			}
+			queryID = savedQuery.ID
+		}
+		digest, err := h.scheduleUsecase.Create(ctx, guildID, i.ChannelID, userID, scope, repositoryName, queryID, mode, cronExpr, timezone)
+		if err != nil {
+			h.respondWithError(s, i, formatScheduleError(err))
+			return
+		}
+		digest.QueryName = queryName
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgScheduleCreated, digest.ID, formatScheduleTarget(digest), formatNextRun(digest)))
+	case ScheduleActionDelete:
+		if id <= 0 {
+			h.respondWithError(s, i, MsgScheduleIDRequired)
+			return
+		}
+		if err := h.scheduleUsecase.Delete(ctx, guildID, userID, id); err != nil {
+			h.respondWithError(s, i, formatScheduleError(err))
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgScheduleDeleted, id))
+	default:
+		digests, err := h.scheduleUsecase.List(ctx, guildID, userID)
+		if err != nil {
+			h.respondWithError(s, i, MsgScheduleListFailed)
+			return
+		}
+		if len(digests) == 0 {
+			h.respondWithSuccess(s, i, MsgNoSchedules)
+			return
+		}
+		lines := make([]string, 0, len(digests))
+		for _, digest := range digests {
+			lines = append(lines, fmt.Sprintf("- #%d `%s` (%s) %s [%s] — 次回: %s",
+				digest.ID, digest.CronExpr, digest.Timezone, formatScheduleTarget(digest), formatScheduleMode(digest), formatNextRun(digest)))
+		}
+		h.respondWithSuccess(s, i, MsgScheduleList+"\n"+strings.Join(lines, "\n"))
+	}
+}
+
+// DeliverDigest は定期ダイジェストを実行し、結果を通知チャンネル（未設定の場合は登録したチャンネル）に投稿します。
+// cmd/bot のスケジューラから呼び出されます。
+func (h *DiscordHandler) DeliverDigest(ctx context.Context, s *discordgo.Session, digest *entity.ScheduledDigest) error {
+	setting, err := h.settingUsecase.GetUserSetting(ctx, digest.GuildID, digest.UserID)
+	if err != nil {
+		return err
+	}
+	channelID := digest.ChannelID
+	if setting != nil {
+		notificationChannelID := setting.NotificationChannelForIssues()
+		if digest.Scope == usecase.ScheduleScopeAssign {
+			notificationChannelID = setting.NotificationChannelForAssign()
+		}
+		if notificationChannelID != "" {
+			channelID = notificationChannelID
+		}
+	}
+
+	header := fmt.Sprintf(MsgDigestHeader, formatUserMention(digest.UserID), digest.ID, formatScheduleTarget(digest))
+	result, err := h.fetchDigestIssues(ctx, digest)
+	if err != nil {
+		sendErr := sendDigestMessage(ctx, s, channelID, digest.UserID, header+"\n"+h.formatIssuesFetchError(err))
+		return errors.Join(err, sendErr)
+	}
+
+	if digest.Mode == usecase.ScheduleModeChanges {
+		return h.deliverDigestChanges(ctx, s, channelID, header, digest, setting, result)
+	}
+
+	if len(result.Issues) == 0 {
+		return sendDigestMessage(ctx, s, channelID, digest.UserID, header+"\n"+MsgNoIssuesFound)
+	}
+
+	embeds := renderIssuesResult(parseRenderMode(nil, setting), result)
+	content := header
+	if extra := buildResultContent(result); extra != "" {
+		content += "\n" + extra
+	}
+	h.sendEmbedsToChannel(s, channelID, digest.UserID, content, issueListItems(result.Issues), embeds)
+	return nil
+}
+
+// fetchDigestIssues は定期ダイジェストの対象に応じて /assign・/issues・/query action:run と同じ取得を行います
+func (h *DiscordHandler) fetchDigestIssues(ctx context.Context, digest *entity.ScheduledDigest) (*usecase.IssuesResult, error) {
+	switch digest.Scope {
+	case usecase.ScheduleScopeAssign:
+		return h.issuesUsecase.GetAssignedIssues(ctx, digest.GuildID, digest.UserID, usecase.IssuesOptions{})
+	case usecase.ScheduleScopeQuery:
+		savedQuery, err := h.queryUsecase.GetByID(ctx, digest.GuildID, digest.UserID, digest.QueryID)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := usecase.NewIssuesOptions(savedQuery.Filter, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return h.fetchIssuesByRepository(ctx, digest.GuildID, digest.UserID, parseRepositoryInput(savedQuery.Repository), opts)
+	default:
+		return h.fetchIssuesByRepository(ctx, digest.GuildID, digest.UserID, parseRepositoryInput(digest.Repository), usecase.IssuesOptions{})
+	}
+}
+
+// deliverDigestChanges は取得結果を前回のスナップショットと比較し、変更があった Issue だけを投稿します。
+// 初回は監視を開始したことだけを投稿し、変更がない場合は何も投稿しません。
+func (h *DiscordHandler) deliverDigestChanges(ctx context.Context, s *discordgo.Session, channelID, header string, digest *entity.ScheduledDigest, setting *entity.UserSetting, result *usecase.IssuesResult) error {
+	login := ""
+	if setting != nil {
+		login = setting.TokenLogin
+	}
+	report, err := h.watchUsecase.DetectChanges(ctx, digest.GuildID, digest.UserID, usecase.SnapshotScope(digest), login, result)
+	if err != nil {
+		return err
+	}
+
+	if report.Baseline {
+		return sendDigestMessage(ctx, s, channelID, digest.UserID, fmt.Sprintf(MsgDigestBaseline, header, report.Total))
+	}
+	if len(report.Changes) == 0 {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf(MsgDigestChanges, header, len(report.Changes))}
+	for _, change := range report.Changes {
+		lines = append(lines, formatIssueChange(change))
+	}
+	if result.Truncated {
+		lines = append(lines, MsgResultsTruncated)
+	}
+	for _, message := range splitMessageLines(lines, MaxMessageLength) {
+		if err := sendDigestMessage(ctx, s, channelID, digest.UserID, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendDigestMessage は定期ダイジェストのテキストを投稿します。
+// 本文には GitHub の Issue のタイトルなど外部の文字列が含まれるため、メンションは登録したユーザーへの通知だけを許可します
+// （"@everyone" やロールのメンションを含むタイトルで通知が飛ばないようにする）。
+func sendDigestMessage(ctx context.Context, s *discordgo.Session, channelID, userID, content string) error {
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: userOnlyMentions(userID),
+	}, discordgo.WithContext(ctx))
+	return err
+}
+
+// formatScheduleTarget は定期ダイジェストの対象を表示用に変換します
+func formatScheduleTarget(digest *entity.ScheduledDigest) string {
+	switch digest.Scope {
+	case usecase.ScheduleScopeAssign:
+		return "/assign"
+	case usecase.ScheduleScopeQuery:
+		return "/query " + digest.QueryName
+	default:
+		return "/issues " + digest.Repository
+	}
+}
+
+// formatScheduleMode は定期ダイジェストの投稿内容を表示用に変換します
+func formatScheduleMode(digest *entity.ScheduledDigest) string {
+	if digest.Mode == usecase.ScheduleModeChanges {
+		return "変更のみ"
+	}
+	return "すべて"
+}
+
+// formatIssueChange は Issue の変更1件を表示用に変換します
+func formatIssueChange(change usecase.IssueChange) string {
+	issue := change.Issue
+	link := fmt.Sprintf("[%s#%d](<%s>) %s", issue.Repository, issue.Number, issue.URL, issue.Title)
+	switch change.Kind {
+	case usecase.IssueChangeClosed:
+		return fmt.Sprintf(MsgIssueChangeClosed, link)
+	case usecase.IssueChangeAssigned:
+		return fmt.Sprintf(MsgIssueChangeAssigned, link)
+	case usecase.IssueChangeLabels:
+		var diffs []string
+		for _, label := range change.AddedLabels {
+			diffs = append(diffs, "+"+label)
+		}
+		for _, label := range change.RemovedLabels {
+			diffs = append(diffs, "-"+label)
+		}
+		return fmt.Sprintf(MsgIssueChangeLabels, link, strings.Join(diffs, ", "))
+	default:
+		return fmt.Sprintf(MsgIssueChangeNew, link)
+	}
+}
+
+// splitMessageLines は行を limit 文字以内のメッセージにまとめます（limit を超える行は切り詰めます）
+func splitMessageLines(lines []string, limit int) []string {
+	var messages []string
+	var current []string
+	length := 0
+	for _, line := range lines {
+		if runes := []rune(line); len(runes) > limit {
+			line = string(runes[:limit-1]) + "…"
+		}
+		lineLength := utf8.RuneCountInString(line)
+		if len(current) > 0 && length+1+lineLength > limit {
+			messages = append(messages, strings.Join(current, "\n"))
+			current, length = nil, 0
+		}
+		if len(current) > 0 {
+			length++
+		}
+		current = append(current, line)
+		length += lineLength
+	}
+	if len(current) > 0 {
+		messages = append(messages, strings.Join(current, "\n"))
+	}
+	return messages
+}
+
+func formatNextRun(digest *entity.ScheduledDigest) string {
+	return usecase.NextRunIn(digest).Format("2006-01-02 15:04 MST")
+}
+
+// formatScheduleError は /schedule のエラーをメッセージに変換します
+func formatScheduleError(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSchedule):
+		return MsgScheduleInvalidCron
+	case errors.Is(err, usecase.ErrInvalidTimezone):
+		return MsgScheduleInvalidTimezone
+	case errors.Is(err, usecase.ErrScheduleTooFrequent):
+		return MsgScheduleTooFrequent
+	case errors.Is(err, usecase.ErrScheduleLimitReached):
+		return fmt.Sprintf(MsgScheduleLimitReached, usecase.MaxSchedulesPerUser)
+	case errors.Is(err, usecase.ErrScheduleNotFound):
+		return MsgScheduleNotFound
+	case errors.Is(err, usecase.ErrScheduleRepositoryMissing):
+		return MsgInvalidRepoFormat
+	case errors.Is(err, usecase.ErrScheduleQueryMissing):
+		return MsgScheduleQueryRequired
+	default:
+		return MsgScheduleSaveFailed
+	}
+}
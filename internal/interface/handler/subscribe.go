@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /subscribe の操作
+const (
+	SubscribeActionAdd    = "add"
+	SubscribeActionList   = "list"
+	SubscribeActionRemove = "remove"
+)
+
+// subscribeCommand は /subscribe コマンドの定義です。チャンネルの管理権限を持つメンバーのみが実行できます。
+func subscribeCommand() *discordgo.ApplicationCommand {
+	manageChannels := int64(discordgo.PermissionManageChannels)
+	minID := float64(1)
+	return &discordgo.ApplicationCommand{
+		Name:                     "subscribe",
+		Description:              "リポジトリのイベントをチャンネルに投稿するフィードを設定します",
+		DefaultMemberPermissions: &manageChannels,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "実行する操作",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "追加", Value: SubscribeActionAdd},
+					{Name: "一覧", Value: SubscribeActionList},
+					{Name: "削除", Value: SubscribeActionRemove},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "repository",
+				Description: "対象のリポジトリ (owner/repo・owner/*・owner)。action:add で必須",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "events",
+				Description: "投稿するイベント (カンマ区切り, 既定: issue_opened)。例: issue_opened,pr_opened",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "labels",
+				Description: "いずれかのラベルが付いたものだけを投稿 (カンマ区切り)。例: bug,security",
+				Required:    false,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "投稿先のチャンネル (既定: このチャンネル)",
+				Required:     false,
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "id",
+				Description: "削除するフィードの ID (action:remove で必須)",
+				Required:    false,
+				MinValue:    &minID,
+			},
+		},
+	}
+}
+
+func (h *DiscordHandler) handleSubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// DefaultMemberPermissions はサーバー側で上書きできるため、実行時にも権限を確認する
+	if i.Member == nil || i.Member.Permissions&discordgo.PermissionManageChannels == 0 {
+		h.respondWithError(s, i, MsgManageChannelsRequired)
+		return
+	}
+
+	action := SubscribeActionList
+	channelID := i.ChannelID
+	var repositoryPattern string
+	var events, labels []string
+	var id int64
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "action":
+			action = opt.StringValue()
+		case "repository":
+			repositoryPattern = strings.TrimSpace(opt.StringValue())
+		case "events":
+			events = splitCommaList(opt.StringValue())
+		case "labels":
+			labels = splitCommaList(opt.StringValue())
+		case "channel":
+			channelID = opt.ChannelValue(nil).ID
+		case "id":
+			id = opt.IntValue()
+		}
+	}
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	switch action {
+	case SubscribeActionAdd:
+		if !isValidExcludePattern(repositoryPattern) {
+			h.respondWithError(s, i, MsgWebhookInvalidPattern)
+			return
+		}
+		// channel で別のチャンネルを指定した場合は、投稿先のチャンネルでも管理権限を持つか確認する
+		if channelID != i.ChannelID {
+			permissions, err := s.UserChannelPermissions(i.Member.User.ID, channelID, discordgo.WithContext(ctx))
+			if err != nil || permissions&discordgo.PermissionManageChannels == 0 {
+				h.respondWithError(s, i, MsgManageChannelsRequired)
+				return
+			}
+		}
+		subscription, err := h.subscriptionUsecase.Add(ctx, i.GuildID, channelID, i.Member.User.ID, repositoryPattern, events, labels)
+		if err != nil {
+			h.respondWithError(s, i, formatSubscribeError(err))
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgSubscriptionCreated, subscription.ID, formatSubscription(subscription)))
+	case SubscribeActionRemove:
+		if id <= 0 {
+			h.respondWithError(s, i, MsgSubscriptionIDRequired)
+			return
+		}
+		if err := h.subscriptionUsecase.Remove(ctx, i.GuildID, id); err != nil {
+			h.respondWithError(s, i, formatSubscribeError(err))
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgSubscriptionDeleted, id))
+	default:
+		subscriptions, err := h.subscriptionUsecase.List(ctx, i.GuildID)
+		if err != nil {
+			h.respondWithError(s, i, MsgSubscriptionListFailed)
+			return
+		}
+		if len(subscriptions) == 0 {
+			h.respondWithSuccess(s, i, MsgNoSubscriptions)
+			return
+		}
+		lines := make([]string, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			lines = append(lines, fmt.Sprintf("- #%d %s", subscription.ID, formatSubscription(subscription)))
+		}
+		h.respondWithSuccess(s, i, MsgSubscriptionList+"\n"+strings.Join(lines, "\n"))
+	}
+}
+
+// formatSubscription はフィードの内容を表示用に変換します
+func formatSubscription(subscription *entity.ChannelSubscription) string {
+	text := fmt.Sprintf("`%s` → %s (イベント: %s", subscription.RepositoryPattern, formatChannelMention(subscription.ChannelID), strings.Join(subscription.Events, ", "))
+	if len(subscription.Labels) > 0 {
+		text += ", ラベル: " + strings.Join(subscription.Labels, ", ")
+	}
+	return text + ")"
+}
+
+// formatSubscribeError は /subscribe のエラーをメッセージに変換します
+func formatSubscribeError(err error) string {
+	var eventErr *usecase.InvalidFeedEventError
+	switch {
+	case errors.As(err, &eventErr):
+		return fmt.Sprintf(MsgSubscriptionInvalidEvent, eventErr.Event, strings.Join(usecase.FeedEvents, ", "))
+	case errors.Is(err, usecase.ErrSubscriptionLimitReached):
+		return fmt.Sprintf(MsgSubscriptionLimitReached, usecase.MaxChannelSubscriptionsPerGuild)
+	case errors.Is(err, usecase.ErrSubscriptionNotFound):
+		return MsgSubscriptionNotFound
+	default:
+		return MsgSubscriptionSaveFailed
+	}
+}
+
+// splitCommaList はカンマ区切りの入力を分割し、空の要素を除いて返します
+func splitCommaList(input string) []string {
+	var values []string
+	for _, value := range strings.Split(input, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// TokenAlertNotifier は usecase.TokenAlertNotifier の Discord 実装です。
+// ユーザーに DM を送り、DM を受け付けていない場合は通知チャンネルにメンション付きで投稿します。
+type TokenAlertNotifier struct {
+	session *discordgo.Session
+}
+
+func NewTokenAlertNotifier(session *discordgo.Session) *TokenAlertNotifier {
+	return &TokenAlertNotifier{session: session}
+}
+
+func (n *TokenAlertNotifier) NotifyTokenAlert(ctx context.Context, alert usecase.TokenAlert) error {
+	message := formatTokenAlert(alert, time.Now())
+	setting := alert.Setting
+
+	if channel, err := n.session.UserChannelCreate(setting.UserID, discordgo.WithContext(ctx)); err == nil {
+		if _, err := n.session.ChannelMessageSend(channel.ID, message, discordgo.WithContext(ctx)); err == nil {
+			return nil
+		}
+	}
+
+	// 通知チャンネルが未設定の場合は、最後に /setting を実行したチャンネルに投稿する
+	channelID := setting.NotificationChannelForAssign()
+	if channelID == "" {
+		channelID = setting.NotificationChannelForIssues()
+	}
+	if channelID == "" {
+		channelID = setting.ChannelID
+	}
+	_, err := n.session.ChannelMessageSend(channelID, formatUserMention(setting.UserID)+" "+message, discordgo.WithContext(ctx))
+	return err
+}
+
+// formatTokenAlert はトークンの通知をメッセージに変換します
+func formatTokenAlert(alert usecase.TokenAlert, now time.Time) string {
+	switch alert.Kind {
+	case usecase.TokenAlertExpiring:
+		return fmt.Sprintf(MsgTokenAlertExpiring, formatTokenExpiry(alert.ExpiresAt, now))
+	case usecase.TokenAlertExpired:
+		return MsgTokenAlertExpired
+	default:
+		return MsgTokenAlertRevoked
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleTokenStatus は /setting action:status で登録済みトークンの認証ユーザー・スコープ・有効期限を表示します
+func (h *DiscordHandler) handleTokenStatus(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	setting, err := h.settingUsecase.GetUserSetting(ctx, i.GuildID, i.Member.User.ID)
+	if err != nil {
+		h.respondWithError(s, i, MsgTokenStatusFailed)
+		return
+	}
+
+	if setting == nil || setting.EncryptedToken == "" {
+		// 個人のトークンがない場合は、サーバーに紐付いた GitHub App を案内する
+		if h.appInstallationUsecase.Enabled() {
+			installation, err := h.appInstallationUsecase.Get(ctx, i.GuildID)
+			if err == nil && installation != nil {
+				h.respondWithSuccess(s, i, fmt.Sprintf(MsgTokenStatusAppOnly, installation.AccountLogin))
+				return
+			}
+		}
+		h.respondWithError(s, i, MsgTokenNotFound)
+		return
+	}
+
+	message := fmt.Sprintf(MsgTokenStatus,
+		formatTokenKind(setting),
+		formatTokenLogin(setting.TokenLogin),
+		formatTokenScopes(setting.TokenScopes),
+		formatTokenExpiry(setting.TokenExpiresAt, time.Now()),
+		formatTokenBaseURL(setting.GitHubBaseURL),
+		setting.UpdatedAt.Format("2006-01-02 15:04"),
+	)
+	if !setting.TokenInvalidAt.IsZero() {
+		message += fmt.Sprintf(MsgTokenStatusInvalid, setting.TokenInvalidAt.Local().Format("2006-01-02 15:04"))
+	}
+	h.respondWithSuccess(s, i, message)
+}
+
+// formatTokenKind はトークンの種類（/login で取得したものか）を表示用に変換します
+func formatTokenKind(setting *entity.UserSetting) string {
+	if setting.EncryptedRefreshToken != "" {
+		return "`/login` (期限前に自動更新)"
+	}
+	return "トークン"
+}
+
+func formatTokenLogin(login string) string {
+	if login == "" {
+		return "未取得 (トークンを再登録すると表示されます)"
+	}
+	return login
+}
+
+// formatTokenScopes はスコープを表示用に変換します（nil はスコープを判別できないトークン）
+func formatTokenScopes(scopes []string) string {
+	if scopes == nil {
+		return "判別できません (Fine-grained PAT / GitHub App のトークン)"
+	}
+	if len(scopes) == 0 {
+		return "なし"
+	}
+	return "`" + strings.Join(scopes, "`, `") + "`"
+}
+
+// formatTokenExpiry は有効期限と残り日数を表示用に変換します
+func formatTokenExpiry(expiresAt, now time.Time) string {
+	if expiresAt.IsZero() {
+		return "なし"
+	}
+	formatted := expiresAt.Local().Format("2006-01-02 15:04")
+	remaining := expiresAt.Sub(now)
+	if remaining <= 0 {
+		return formatted + " (期限切れ)"
+	}
+	if remaining < 24*time.Hour {
+		return formatted + " (残り1日未満)"
+	}
+	return fmt.Sprintf("%s (残り%d日)", formatted, int(remaining.Hours()/24))
+}
+
+func formatTokenBaseURL(baseURL string) string {
+	if baseURL == "" {
+		return "既定のホスト"
+	}
+	return baseURL
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /webhook の操作
+const (
+	WebhookActionAdd    = "add"
+	WebhookActionList   = "list"
+	WebhookActionRemove = "remove"
+)
+
+// webhookCommand は /webhook コマンドの定義です。チャンネルの管理権限を持つメンバーのみが実行できます。
+func webhookCommand() *discordgo.ApplicationCommand {
+	manageChannels := int64(discordgo.PermissionManageChannels)
+	minID := float64(1)
+	return &discordgo.ApplicationCommand{
+		Name:                     "webhook",
+		Description:              "GitHub webhook の通知をこのチャンネルに投稿します",
+		DefaultMemberPermissions: &manageChannels,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "実行する操作",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "追加", Value: WebhookActionAdd},
+					{Name: "一覧", Value: WebhookActionList},
+					{Name: "削除", Value: WebhookActionRemove},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "repository",
+				Description: "通知するリポジトリ (owner/repo・owner/*・owner)。action:add で必須",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "post_all",
+				Description: "すべてのイベントをこのチャンネルに投稿する (既定: true)。false の場合は /subscribe のフィードにだけ投稿",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "id",
+				Description: "削除する webhook の ID (action:remove で必須)",
+				Required:    false,
+				MinValue:    &minID,
+			},
+		},
+	}
+}
+
+func (h *DiscordHandler) handleWebhookCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// DefaultMemberPermissions はサーバー側で上書きできるため、実行時にも権限を確認する
+	if i.Member == nil || i.Member.Permissions&discordgo.PermissionManageChannels == 0 {
+		h.respondWithError(s, i, MsgManageChannelsRequired)
+		return
+	}
+
+	action := WebhookActionList
+	var repositoryPattern string
+	var id int64
+	postAll := true
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "action":
+			action = opt.StringValue()
+		case "repository":
+			repositoryPattern = strings.TrimSpace(opt.StringValue())
+		case "post_all":
+			postAll = opt.BoolValue()
+		case "id":
+			id = opt.IntValue()
+		}
+	}
+
+	ctx, cancel := h.newContext()
+	defer cancel()
+
+	switch action {
+	case WebhookActionAdd:
+		if !isValidExcludePattern(repositoryPattern) {
+			h.respondWithError(s, i, MsgWebhookInvalidPattern)
+			return
+		}
+		// post_all:false の場合は投稿先のチャンネルを持たず、署名の検証と /subscribe のフィードへの振り分けだけに使う
+		channelID := ""
+		if postAll {
+			channelID = i.ChannelID
+		}
+		subscription, secret, err := h.webhookUsecase.Subscribe(ctx, i.GuildID, channelID, i.Member.User.ID, repositoryPattern)
+		if err != nil {
+			h.respondWithError(s, i, formatWebhookError(err))
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgWebhookCreated,
+			subscription.ID, subscription.RepositoryPattern, formatWebhookTarget(subscription),
+			h.webhookUsecase.PayloadURL(), secret))
+	case WebhookActionRemove:
+		if id <= 0 {
+			h.respondWithError(s, i, MsgWebhookIDRequired)
+			return
+		}
+		if err := h.webhookUsecase.Unsubscribe(ctx, i.GuildID, id); err != nil {
+			h.respondWithError(s, i, formatWebhookError(err))
+			return
+		}
+		h.respondWithSuccess(s, i, fmt.Sprintf(MsgWebhookDeleted, id))
+	default:
+		subscriptions, err := h.webhookUsecase.List(ctx, i.GuildID)
+		if err != nil {
+			h.respondWithError(s, i, MsgWebhookListFailed)
+			return
+		}
+		if len(subscriptions) == 0 {
+			h.respondWithSuccess(s, i, MsgNoWebhooks)
+			return
+		}
+		lines := make([]string, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			lines = append(lines, fmt.Sprintf("- #%d `%s` → %s (登録: %s)",
+				subscription.ID, subscription.RepositoryPattern, formatWebhookTarget(subscription), formatUserMention(subscription.CreatedBy)))
+		}
+		h.respondWithSuccess(s, i, MsgWebhookList+"\n"+strings.Join(lines, "\n"))
+	}
+}
+
+// formatWebhookTarget は webhook の投稿先を表示用に変換します
+func formatWebhookTarget(subscription *entity.WebhookSubscription) string {
+	if !subscription.PostsAllEvents() {
+		return MsgWebhookFeedsOnly
+	}
+	return formatChannelMention(subscription.ChannelID)
+}
+
+// formatWebhookError は /webhook のエラーをメッセージに変換します
+func formatWebhookError(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrWebhookNotConfigured):
+		return MsgWebhookNotConfigured
+	case errors.Is(err, usecase.ErrWebhookLimitReached):
+		return fmt.Sprintf(MsgWebhookLimitReached, usecase.MaxWebhookSubscriptionsPerGuild)
+	case errors.Is(err, usecase.ErrWebhookNotFound):
+		return MsgWebhookNotFound
+	default:
+		return MsgWebhookSaveFailed
+	}
+}
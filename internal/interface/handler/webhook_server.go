@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// WebhookPath は GitHub webhook の配信を受け付けるパスです
+	WebhookPath = "/webhooks/github"
+	// maxWebhookPayloadBytes は受け付ける配信内容の上限です（GitHub の上限と同じ 25MB）
+	maxWebhookPayloadBytes = 25 << 20
+	// webhookDeliveryTimeout は1件の配信を Discord に投稿するまでの時間の上限です（GitHub は 10 秒で打ち切る）
+	webhookDeliveryTimeout = 8 * time.Second
+	// maxWebhookBodyLength はコメント・レビュー本文の表示文字数の上限です
+	maxWebhookBodyLength = 300
+)
+
+// WebhookRouter は配信の投稿先を解決します（usecase.WebhookUsecase が実装します）
+type WebhookRouter interface {
+	Route(ctx context.Context, repositoryFullName string, payload []byte, signature string) ([]*entity.WebhookSubscription, error)
+}
+
+// FeedMatcher は配信に一致する /subscribe のフィードを返します（usecase.SubscriptionUsecase が実装します）
+type FeedMatcher interface {
+	Match(ctx context.Context, guildIDs []string, repositoryFullName, eventType, action string, labels []string) ([]*entity.ChannelSubscription, error)
+}
+
+// MessageSender は Discord のチャンネルにメッセージを送信します（*discordgo.Session が実装します）
+type MessageSender interface {
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// WebhookHandler は GitHub webhook の配信を受け付け、署名を検証して登録されたチャンネルに投稿する http.Handler です。
+// /webhook で投稿先に指定したチャンネルにはすべてのイベントを、/subscribe のフィードには一致するイベントだけを投稿します。
+// GitHub や Discord に接続せずに動作を確認できるよう、投稿先の解決と送信はインターフェースで受け取ります。
+type WebhookHandler struct {
+	router WebhookRouter
+	feeds  FeedMatcher
+	sender MessageSender
+}
+
+func NewWebhookHandler(router WebhookRouter, feeds FeedMatcher, sender MessageSender) *WebhookHandler {
+	return &WebhookHandler{router: router, feeds: feeds, sender: sender}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	eventType := r.Header.Get(github.WebhookEventHeader)
+	deliveryID := r.Header.Get(github.WebhookDeliveryHeader)
+	if eventType == github.WebhookEventPing {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "pong")
+		return
+	}
+	if !isSupportedWebhookEvent(eventType) {
+		// 投稿しないイベントは署名を検証せずに無視する
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayloadBytes))
+	if err != nil {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	event, err := github.ParseWebhookEvent(payload)
+	if err != nil || event.RepositoryFullName() == "" {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), webhookDeliveryTimeout)
+	defer cancel()
+
+	subscriptions, err := h.router.Route(ctx, event.RepositoryFullName(), payload, r.Header.Get(github.WebhookSignatureHeader))
+	switch {
+	case errors.Is(err, usecase.ErrWebhookNotSubscribed):
+		http.Error(w, "no subscription for repository", http.StatusNotFound)
+		return
+	case errors.Is(err, usecase.ErrWebhookSignatureMismatch):
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	case err != nil:
+		log.Printf("Failed to route webhook delivery %s: %v", deliveryID, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	embed := formatWebhookEvent(eventType, event)
+	if embed == nil {
+		// 投稿しない action (edited など)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	failed := 0
+	for _, channelID := range h.deliveryChannels(ctx, deliveryID, eventType, event, subscriptions) {
+		_, err := h.sender.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		}, discordgo.WithContext(ctx))
+		if err != nil {
+			log.Printf("Failed to post webhook delivery %s to channel %s: %v", deliveryID, channelID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		// GitHub の配信履歴で失敗として確認・再送できるようにする
+		http.Error(w, "failed to post to discord", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deliveryChannels は webhook の投稿先のチャンネル（post_all:false の登録は除く）と、
+// 署名を検証できたサーバーのフィードのうち配信に一致するもののチャンネルを重複なく返します
+func (h *WebhookHandler) deliveryChannels(ctx context.Context, deliveryID, eventType string, event *github.WebhookEvent, subscriptions []*entity.WebhookSubscription) []string {
+	var channelIDs, guildIDs []string
+	seenChannels := make(map[string]bool)
+	seenGuilds := make(map[string]bool)
+	for _, subscription := range subscriptions {
+		if subscription.PostsAllEvents() && !seenChannels[subscription.ChannelID] {
+			seenChannels[subscription.ChannelID] = true
+			channelIDs = append(channelIDs, subscription.ChannelID)
+		}
+		if !seenGuilds[subscription.GuildID] {
+			seenGuilds[subscription.GuildID] = true
+			guildIDs = append(guildIDs, subscription.GuildID)
+		}
+	}
+
+	if h.feeds == nil {
+		return channelIDs
+	}
+	feeds, err := h.feeds.Match(ctx, guildIDs, event.RepositoryFullName(), eventType, event.Action, webhookEventLabels(event))
+	if err != nil {
+		// フィードを取得できなくても webhook の登録チャンネルには投稿する
+		log.Printf("Failed to match channel subscriptions for webhook delivery %s: %v", deliveryID, err)
+		return channelIDs
+	}
+	for _, feed := range feeds {
+		if !seenChannels[feed.ChannelID] {
+			seenChannels[feed.ChannelID] = true
+			channelIDs = append(channelIDs, feed.ChannelID)
+		}
+	}
+	return channelIDs
+}
+
+// webhookEventLabels はフィードのラベルの絞り込みに使うラベルを返します。
+// labeled の場合は追加されたラベルだけを使い、それ以外は Issue / Pull Request に付いているラベルを使います。
+func webhookEventLabels(event *github.WebhookEvent) []string {
+	if event.Action == "labeled" && event.Label != nil {
+		return []string{event.Label.Name}
+	}
+	var labels []github.Label
+	switch {
+	case event.Issue != nil:
+		labels = event.Issue.Labels
+	case event.PullRequest != nil:
+		labels = event.PullRequest.Labels
+	}
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+func isSupportedWebhookEvent(eventType string) bool {
+	switch eventType {
+	case github.WebhookEventIssues, github.WebhookEventIssueComment, github.WebhookEventPullRequest, github.WebhookEventPullRequestReview:
+		return true
+	}
+	return false
+}
+
+// formatWebhookEvent は配信内容を embed に変換します。投稿しない action の場合は nil を返します。
+func formatWebhookEvent(eventType string, event *github.WebhookEvent) *discordgo.MessageEmbed {
+	switch eventType {
+	case github.WebhookEventIssues:
+		return formatIssuesWebhookEvent(event)
+	case github.WebhookEventIssueComment:
+		return formatIssueCommentWebhookEvent(event)
+	case github.WebhookEventPullRequest:
+		return formatPullRequestWebhookEvent(event)
+	case github.WebhookEventPullRequestReview:
+		return formatPullRequestReviewWebhookEvent(event)
+	}
+	return nil
+}
+
+func formatIssuesWebhookEvent(event *github.WebhookEvent) *discordgo.MessageEmbed {
+	issue := event.Issue
+	if issue == nil {
+		return nil
+	}
+
+	var description string
+	color := ColorGitHubSuccess
+	switch event.Action {
+	case "opened":
+		description = MsgWebhookIssueOpened
+	case "closed":
+		description = MsgWebhookIssueClosed
+		color = ColorGitHubClosed
+	case "reopened":
+		description = MsgWebhookIssueReopened
+	case "assigned":
+		description = fmt.Sprintf(MsgWebhookIssueAssigned, webhookLogin(event.Assignee))
+	case "labeled":
+		if event.Label == nil {
+			return nil
+		}
+		description = fmt.Sprintf(MsgWebhookIssueLabeled, event.Label.Name)
+	default:
+		return nil
+	}
+	return newWebhookEmbed(event, IconIssue, issue.Number, issue.Title, issue.HTMLURL, description, color)
+}
+
+func formatIssueCommentWebhookEvent(event *github.WebhookEvent) *discordgo.MessageEmbed {
+	issue := event.Issue
+	if issue == nil || event.Comment == nil || event.Action != "created" {
+		return nil
+	}
+	icon := IconIssue
+	if issue.IsPullRequest() {
+		icon = IconPullRequest
+	}
+	embed := newWebhookEmbed(event, icon, issue.Number, issue.Title, event.Comment.HTMLURL, MsgWebhookCommented, ColorGitHubPullRequest)
+	if body := truncateWebhookBody(event.Comment.Body); body != "" {
+		embed.Description += "\n" + body
+	}
+	return embed
+}
+
+func formatPullRequestWebhookEvent(event *github.WebhookEvent) *discordgo.MessageEmbed {
+	pr := event.PullRequest
+	if pr == nil {
+		return nil
+	}
+
+	var description string
+	color := ColorGitHubPullRequest
+	switch event.Action {
+	case "opened":
+		description = MsgWebhookPullRequestOpened
+		if pr.Draft {
+			description = MsgWebhookPullRequestDraftOpened
+			color = ColorGitHubDraft
+		}
+	case "closed":
+		description = MsgWebhookPullRequestClosed
+		color = ColorGitHubDanger
+		if pr.Merged {
+			description = MsgWebhookPullRequestMerged
+			color = ColorGitHubClosed
+		}
+	case "reopened":
+		description = MsgWebhookPullRequestReopened
+	case "ready_for_review":
+		description = MsgWebhookPullRequestReady
+	case "review_requested":
+		if event.RequestedReviewer == nil {
+			// チームへの依頼は投稿しない
+			return nil
+		}
+		description = fmt.Sprintf(MsgWebhookReviewRequested, event.RequestedReviewer.Login)
+	default:
+		return nil
+	}
+	return newWebhookEmbed(event, IconPullRequest, pr.Number, pr.Title, pr.HTMLURL, description, color)
+}
+
+func formatPullRequestReviewWebhookEvent(event *github.WebhookEvent) *discordgo.MessageEmbed {
+	pr := event.PullRequest
+	review := event.Review
+	if pr == nil || review == nil || event.Action != "submitted" {
+		return nil
+	}
+
+	var description string
+	color := ColorGitHubPullRequest
+	switch strings.ToLower(review.State) {
+	case "approved":
+		description = MsgWebhookReviewApproved
+		color = ColorGitHubSuccess
+	case "changes_requested":
+		description = MsgWebhookReviewChangesRequested
+		color = ColorGitHubDanger
+	case "commented":
+		if review.Body == "" {
+			// 行コメントだけのレビューは issue_comment と重複しやすいため投稿しない
+			return nil
+		}
+		description = MsgWebhookReviewCommented
+	default:
+		return nil
+	}
+	embed := newWebhookEmbed(event, IconPullRequest, pr.Number, pr.Title, review.HTMLURL, description, color)
+	if body := truncateWebhookBody(review.Body); body != "" {
+		embed.Description += "\n" + body
+	}
+	return embed
+}
+
+// newWebhookEmbed は webhook のイベントを表す embed を生成します
+func newWebhookEmbed(event *github.WebhookEvent, icon string, number int, title, url, description string, color int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       truncateRunes(fmt.Sprintf("%s %s#%d %s", icon, event.RepositoryFullName(), number, title), 256),
+		URL:         url,
+		Description: description,
+		Color:       color,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if event.Sender != nil {
+		embed.Author = &discordgo.MessageEmbedAuthor{Name: event.Sender.Login}
+	}
+	return embed
+}
+
+func webhookLogin(user *github.User) string {
+	if user == nil {
+		return "不明"
+	}
+	return user.Login
+}
+
+// truncateWebhookBody はコメント・レビュー本文を maxWebhookBodyLength 文字までの引用に変換します
+func truncateWebhookBody(body string) string {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return ""
+	}
+	return "> " + strings.ReplaceAll(truncateRunes(body, maxWebhookBodyLength), "\n", "\n> ")
+}
+
+// truncateRunes は s を limit 文字までに切り詰めます
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
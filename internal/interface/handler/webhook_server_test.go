@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/infrastructure/crypto"
+	"github-discord-bot/internal/infrastructure/github"
+	"github-discord-bot/internal/usecase"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const testWebhookSecret = "test-webhook-secret"
+
+// memoryWebhookSubscriptionRepository は webhook の登録をメモリ上に保持する repository.WebhookSubscriptionRepository です
+type memoryWebhookSubscriptionRepository struct {
+	subscriptions []*entity.WebhookSubscription
+}
+
+func (r *memoryWebhookSubscriptionRepository) Create(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	subscription.ID = int64(len(r.subscriptions) + 1)
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+func (r *memoryWebhookSubscriptionRepository) FindByGuild(ctx context.Context, guildID string) ([]*entity.WebhookSubscription, error) {
+	var result []*entity.WebhookSubscription
+	for _, subscription := range r.subscriptions {
+		if subscription.GuildID == guildID {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryWebhookSubscriptionRepository) FindByRepository(ctx context.Context, fullName string) ([]*entity.WebhookSubscription, error) {
+	fullName = strings.ToLower(fullName)
+	owner, _, _ := strings.Cut(fullName, "/")
+	var result []*entity.WebhookSubscription
+	for _, subscription := range r.subscriptions {
+		switch strings.ToLower(subscription.RepositoryPattern) {
+		case fullName, owner + "/*", owner:
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryWebhookSubscriptionRepository) Delete(ctx context.Context, guildID string, id int64) (bool, error) {
+	return false, nil
+}
+
+// fakeFeedMatcher はイベントの種類ごとに決めたフィードを返します
+type fakeFeedMatcher struct {
+	feeds map[string][]*entity.ChannelSubscription
+}
+
+func (m *fakeFeedMatcher) Match(ctx context.Context, guildIDs []string, repositoryFullName, eventType, action string, labels []string) ([]*entity.ChannelSubscription, error) {
+	return m.feeds[eventType], nil
+}
+
+// sentMessage は fakeSender に送信されたメッセージです
+type sentMessage struct {
+	channelID string
+	data      *discordgo.MessageSend
+}
+
+// fakeSender は Discord に接続せずに送信内容を記録する MessageSender です
+type fakeSender struct {
+	mu       sync.Mutex
+	messages []sentMessage
+}
+
+func (s *fakeSender) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, sentMessage{channelID: channelID, data: data})
+	return &discordgo.Message{ChannelID: channelID}, nil
+}
+
+// newTestWebhookHandler は acme/api の webhook を #channel-all に登録した WebhookHandler を返します
+func newTestWebhookHandler(t *testing.T, feeds FeedMatcher) (*WebhookHandler, *fakeSender) {
+	t.Helper()
+	aes, err := crypto.NewAESCrypto(strings.Repeat("k", crypto.RequiredKeyLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedSecret, err := aes.Encrypt(testWebhookSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &memoryWebhookSubscriptionRepository{}
+	repo.Create(context.Background(), &entity.WebhookSubscription{
+		GuildID:           "guild-1",
+		ChannelID:         "channel-all",
+		RepositoryPattern: "acme/api",
+		EncryptedSecret:   encryptedSecret,
+		CreatedBy:         "user-1",
+		CreatedAt:         time.Now(),
+	})
+
+	sender := &fakeSender{}
+	router := usecase.NewWebhookUsecase(repo, aes, "https://bot.example.com"+WebhookPath)
+	return NewWebhookHandler(router, feeds, sender), sender
+}
+
+func readWebhookFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func newWebhookRequest(eventType string, payload []byte, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, WebhookPath, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(github.WebhookEventHeader, eventType)
+	req.Header.Set(github.WebhookDeliveryHeader, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	if signature != "" {
+		req.Header.Set(github.WebhookSignatureHeader, signature)
+	}
+	return req
+}
+
+func TestWebhookHandlerSignature(t *testing.T) {
+	payload := readWebhookFixture(t, "webhook_issues_opened.json")
+
+	tests := []struct {
+		name       string
+		signature  string
+		wantStatus int
+		wantSent   int
+	}{
+		{"valid signature", github.SignWebhookPayload(payload, testWebhookSecret), http.StatusNoContent, 1},
+		{"wrong secret", github.SignWebhookPayload(payload, "other-secret"), http.StatusUnauthorized, 0},
+		{"tampered payload", github.SignWebhookPayload(append([]byte(" "), payload...), testWebhookSecret), http.StatusUnauthorized, 0},
+		{"missing signature", "", http.StatusUnauthorized, 0},
+		{"malformed signature", "sha1=deadbeef", http.StatusUnauthorized, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, sender := newTestWebhookHandler(t, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newWebhookRequest(github.WebhookEventIssues, payload, tt.signature))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %q)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if len(sender.messages) != tt.wantSent {
+				t.Errorf("sent %d messages, want %d", len(sender.messages), tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerRejectsOversizedPayload(t *testing.T) {
+	handler, sender := newTestWebhookHandler(t, nil)
+	payload := bytes.Repeat([]byte(" "), maxWebhookPayloadBytes+1)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newWebhookRequest(github.WebhookEventIssues, payload, github.SignWebhookPayload(payload, testWebhookSecret)))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if len(sender.messages) != 0 {
+		t.Errorf("sent %d messages, want 0", len(sender.messages))
+	}
+}
+
+func TestWebhookHandlerUnknownRepository(t *testing.T) {
+	handler, _ := newTestWebhookHandler(t, nil)
+	payload := bytes.ReplaceAll(readWebhookFixture(t, "webhook_issues_opened.json"), []byte(`"acme/api"`), []byte(`"acme/web"`))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newWebhookRequest(github.WebhookEventIssues, payload, github.SignWebhookPayload(payload, testWebhookSecret)))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestWebhookHandlerRouting(t *testing.T) {
+	feeds := &fakeFeedMatcher{feeds: map[string][]*entity.ChannelSubscription{
+		github.WebhookEventIssues: {
+			{ID: 1, GuildID: "guild-1", ChannelID: "channel-triage"},
+			// webhook の登録チャンネルと同じフィードには 1 回だけ投稿する
+			{ID: 2, GuildID: "guild-1", ChannelID: "channel-all"},
+		},
+	}}
+
+	tests := []struct {
+		name         string
+		eventType    string
+		fixture      string
+		wantChannels []string
+		wantTitle    string
+		wantURL      string
+		wantDesc     string
+	}{
+		{
+			name:         "issue opened",
+			eventType:    github.WebhookEventIssues,
+			fixture:      "webhook_issues_opened.json",
+			wantChannels: []string{"channel-all", "channel-triage"},
+			wantTitle:    IconIssue + " acme/api#42 Crash when the config file is empty",
+			wantURL:      "https://github.com/acme/api/issues/42",
+			wantDesc:     MsgWebhookIssueOpened,
+		},
+		{
+			name:         "pull request merged",
+			eventType:    github.WebhookEventPullRequest,
+			fixture:      "webhook_pull_request_closed.json",
+			wantChannels: []string{"channel-all"},
+			wantTitle:    IconPullRequest + " acme/api#57 Handle empty config files",
+			wantURL:      "https://github.com/acme/api/pull/57",
+			wantDesc:     MsgWebhookPullRequestMerged,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, sender := newTestWebhookHandler(t, feeds)
+			payload := readWebhookFixture(t, tt.fixture)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newWebhookRequest(tt.eventType, payload, github.SignWebhookPayload(payload, testWebhookSecret)))
+
+			if rec.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want %d (body: %q)", rec.Code, http.StatusNoContent, rec.Body.String())
+			}
+			if len(sender.messages) != len(tt.wantChannels) {
+				t.Fatalf("sent %d messages, want %d", len(sender.messages), len(tt.wantChannels))
+			}
+			for i, message := range sender.messages {
+				if message.channelID != tt.wantChannels[i] {
+					t.Errorf("message %d channel = %q, want %q", i, message.channelID, tt.wantChannels[i])
+				}
+				if len(message.data.Embeds) != 1 {
+					t.Fatalf("message %d has %d embeds, want 1", i, len(message.data.Embeds))
+				}
+				embed := message.data.Embeds[0]
+				if embed.Title != tt.wantTitle {
+					t.Errorf("embed title = %q, want %q", embed.Title, tt.wantTitle)
+				}
+				if embed.URL != tt.wantURL {
+					t.Errorf("embed URL = %q, want %q", embed.URL, tt.wantURL)
+				}
+				if embed.Description != tt.wantDesc {
+					t.Errorf("embed description = %q, want %q", embed.Description, tt.wantDesc)
+				}
+				if embed.Author == nil || embed.Author.Name != "octocat" {
+					t.Errorf("embed author = %+v, want octocat", embed.Author)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerIgnoresUnsupportedEvents(t *testing.T) {
+	handler, sender := newTestWebhookHandler(t, nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newWebhookRequest("push", []byte(`{}`), ""))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("push: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newWebhookRequest(github.WebhookEventPing, []byte(`{"zen":"Keep it logically awesome."}`), ""))
+	if rec.Code != http.StatusOK || rec.Body.String() != "pong" {
+		t.Errorf("ping: status = %d body = %q, want 200 pong", rec.Code, rec.Body.String())
+	}
+
+	if len(sender.messages) != 0 {
+		t.Errorf("sent %d messages, want 0", len(sender.messages))
+	}
+}
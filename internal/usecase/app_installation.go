@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// ErrAppNotConfigured は GitHub App の認証情報が Bot に設定されていない場合に返されます
+var ErrAppNotConfigured = errors.New("github app is not configured")
+
+// ErrPersonalTokenRequired は個人のトークンが必要な操作（/assign など）を GitHub App のトークンで実行しようとした場合に返されます
+var ErrPersonalTokenRequired = errors.New("personal token required")
+
+// ErrInstallationNotAccessible は紐付けを実行したユーザーが GitHub 上でインストールにアクセスできない場合に返されます
+var ErrInstallationNotAccessible = errors.New("installation is not accessible by the user")
+
+// ErrAppUserTokenRequired はユーザーのトークンでインストールの一覧 (/user/installations) を取得できない場合に返されます。
+// 一覧は GitHub App の OAuth で取得したユーザーアクセストークンでのみ取得できます。
+var ErrAppUserTokenRequired = errors.New("github app user access token required")
+
+// InstallationAccessVerifier はユーザー自身のトークンで、インストールにアクセスできるかを確認します（IssuesUsecase が実装します）
+type InstallationAccessVerifier interface {
+	VerifyInstallationAccess(ctx context.Context, guildID, userID string, installationID int64) error
+}
+
+// InstallationTokenProvider は GitHub App のインストールアクセストークンを発行します（github.AppAuth が実装します）
+type InstallationTokenProvider interface {
+	GetInstallation(ctx context.Context, installationID int64) (*github.Installation, error)
+	InstallationToken(ctx context.Context, installationID int64) (string, error)
+}
+
+// AppInstallationUsecase は Discord サーバーと GitHub App のインストールの紐付けを管理します
+type AppInstallationUsecase struct {
+	repo repository.GuildAppInstallationRepository
+	app  InstallationTokenProvider
+}
+
+// NewAppInstallationUsecase は AppInstallationUsecase を生成します。
+// app が nil の場合（GitHub App 未設定）は紐付けできず、トークンのフォールバックも行いません。
+func NewAppInstallationUsecase(repo repository.GuildAppInstallationRepository, app InstallationTokenProvider) *AppInstallationUsecase {
+	return &AppInstallationUsecase{
+		repo: repo,
+		app:  app,
+	}
+}
+
+// Enabled は GitHub App の認証情報が設定されているかを返します
+func (u *AppInstallationUsecase) Enabled() bool {
+	return u != nil && u.app != nil
+}
+
+// Link はインストールIDを検証し、Discord サーバーに紐付けます。
+// App の JWT ではどのアカウントのインストールも取得できるため、実行したユーザーがインストールにアクセスできることを
+// verifier で確認してから紐付けます（他の Organization のインストールを紐付けて非公開リポジトリを読めないようにする）。
+func (u *AppInstallationUsecase) Link(ctx context.Context, guildID, userID string, installationID int64, verifier InstallationAccessVerifier) (*entity.GuildAppInstallation, error) {
+	if !u.Enabled() {
+		return nil, ErrAppNotConfigured
+	}
+	if verifier == nil {
+		return nil, ErrInstallationNotAccessible
+	}
+	if err := verifier.VerifyInstallationAccess(ctx, guildID, userID, installationID); err != nil {
+		return nil, err
+	}
+
+	installation, err := u.app.GetInstallation(ctx, installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := &entity.GuildAppInstallation{
+		GuildID:        guildID,
+		InstallationID: installation.ID,
+		AccountLogin:   installation.Account.Login,
+		LinkedBy:       userID,
+		UpdatedAt:      time.Now(),
+	}
+	if err := u.repo.Save(ctx, linked); err != nil {
+		return nil, err
+	}
+	return linked, nil
+}
+
+// Unlink は Discord サーバーとインストールの紐付けを解除します
+func (u *AppInstallationUsecase) Unlink(ctx context.Context, guildID string) error {
+	return u.repo.Delete(ctx, guildID)
+}
+
+// Get は Discord サーバーに紐付いたインストールを返します（未設定の場合は nil）
+func (u *AppInstallationUsecase) Get(ctx context.Context, guildID string) (*entity.GuildAppInstallation, error) {
+	return u.repo.FindByGuild(ctx, guildID)
+}
+
+// installationToken は Discord サーバーに紐付いたインストールのアクセストークンを返します。
+// GitHub App が未設定、または紐付けがない場合は ErrTokenNotFound を返します。
+func (u *AppInstallationUsecase) installationToken(ctx context.Context, guildID string) (string, error) {
+	if !u.Enabled() {
+		return "", ErrTokenNotFound
+	}
+
+	installation, err := u.repo.FindByGuild(ctx, guildID)
+	if err != nil {
+		return "", err
+	}
+	if installation == nil {
+		return "", ErrTokenNotFound
+	}
+	return u.app.InstallationToken(ctx, installation.InstallationID)
+}
+
+// VerifyInstallationAccess はユーザー自身のトークンで /user/installations を取得し、installationID が含まれるかを確認します
+func (u *IssuesUsecase) VerifyInstallationAccess(ctx context.Context, guildID, userID string, installationID int64) error {
+	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
+	if err != nil {
+		return err
+	}
+	if usesInstallationToken(setting) {
+		return ErrPersonalTokenRequired
+	}
+
+	installations, _, err := u.newClient(setting, token).GetAllUserInstallations(ctx)
+	if err != nil {
+		var ghErr *github.GitHubError
+		if errors.As(err, &ghErr) && (ghErr.StatusCode == http.StatusUnauthorized || ghErr.StatusCode == http.StatusForbidden) {
+			return ErrAppUserTokenRequired
+		}
+		return err
+	}
+	for _, installation := range installations {
+		if installation.ID == installationID {
+			return nil
+		}
+	}
+	return ErrInstallationNotAccessible
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// GroupBy は Issue の一覧をまとめる単位です
+type GroupBy string
+
+const (
+	GroupByNone       GroupBy = ""
+	GroupByRepository GroupBy = "repository"
+	GroupByLabel      GroupBy = "label"
+	GroupByAssignee   GroupBy = "assignee"
+	GroupByMilestone  GroupBy = "milestone"
+)
+
+// IssueGroup は GroupIssues でまとめた Issue です
+type IssueGroup struct {
+	By     GroupBy
+	Name   string // リポジトリ名・ラベル名・担当者の login・マイルストーンのタイトル（該当なしの場合は空）
+	Issues []github.Issue
+}
+
+// SortIssues は issues を key の direction の順に並べ替えます（同じ値の Issue は元の順序を保ちます）。
+// key が空の場合は作成日時、direction が空の場合は降順です。age は作成からの経過時間で、降順は古い順です。
+// 複数のリポジトリ・検索クエリの結果を結合した後に、全体の並び順を揃えるために使います。
+func SortIssues(issues []github.Issue, key github.IssueSort, direction string) {
+	desc := direction != github.SortDirectionAsc
+	if key == github.IssueSortAge {
+		desc = !desc
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if desc {
+			a, b = b, a
+		}
+		switch key {
+		case github.IssueSortUpdated:
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		case github.IssueSortComments:
+			return a.Comments < b.Comments
+		default:
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	})
+}
+
+// GroupIssues は issues を by ごとにまとめ、名前順（該当なしのグループは最後）に返します。
+// グループ内の順序は issues の順序を保ちます。ラベル・担当者が複数ある Issue は、それぞれのグループに含めます。
+// by が GroupByNone の場合は nil を返します。
+func GroupIssues(issues []github.Issue, by GroupBy) []IssueGroup {
+	if by == GroupByNone {
+		return nil
+	}
+
+	index := make(map[string]int)
+	var groups []IssueGroup
+	for _, issue := range issues {
+		names := groupNames(issue, by)
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, name := range names {
+			idx, ok := index[name]
+			if !ok {
+				idx = len(groups)
+				index[name] = idx
+				groups = append(groups, IssueGroup{By: by, Name: name})
+			}
+			groups[idx].Issues = append(groups[idx].Issues, issue)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Name, groups[j].Name
+		if a == "" || b == "" {
+			return a != ""
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	return groups
+}
+
+// groupNames は Issue が含まれるグループの名前を返します
+func groupNames(issue github.Issue, by GroupBy) []string {
+	var names []string
+	switch by {
+	case GroupByRepository:
+		if issue.Repository != nil {
+			names = append(names, issue.Repository.FullName)
+		}
+	case GroupByLabel:
+		for _, label := range issue.Labels {
+			names = append(names, label.Name)
+		}
+	case GroupByAssignee:
+		for _, assignee := range issue.Assignees {
+			names = append(names, assignee.Login)
+		}
+	case GroupByMilestone:
+		if issue.Milestone != nil {
+			names = append(names, issue.Milestone.Title)
+		}
+	}
+	return names
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+var arrangeTestBase = time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)
+
+// arrangeIssue は作成日時・更新日時を基準の日時からの日数で指定した Issue を返します
+func arrangeIssue(number, createdDay, updatedDay, comments int) github.Issue {
+	return github.Issue{
+		Number:    number,
+		CreatedAt: arrangeTestBase.AddDate(0, 0, createdDay),
+		UpdatedAt: arrangeTestBase.AddDate(0, 0, updatedDay),
+		Comments:  comments,
+	}
+}
+
+func issueNumbers(issues []github.Issue) []int {
+	numbers := []int{}
+	for _, issue := range issues {
+		numbers = append(numbers, issue.Number)
+	}
+	return numbers
+}
+
+func TestSortIssues(t *testing.T) {
+	// #1: 最も古く作成・最も新しく更新 / #2: 中間・コメント最多 / #3: 最も新しく作成・最も古く更新 / #4: #2 と同じ値
+	issues := []github.Issue{
+		arrangeIssue(2, 1, 1, 5),
+		arrangeIssue(1, 0, 2, 1),
+		arrangeIssue(4, 1, 1, 5),
+		arrangeIssue(3, 2, 0, 0),
+	}
+
+	tests := []struct {
+		name      string
+		key       github.IssueSort
+		direction string
+		want      []int
+	}{
+		{"default is newest created first", "", "", []int{3, 2, 4, 1}},
+		{"created asc", github.IssueSortCreated, github.SortDirectionAsc, []int{1, 2, 4, 3}},
+		{"updated desc", github.IssueSortUpdated, github.SortDirectionDesc, []int{1, 2, 4, 3}},
+		{"updated asc", github.IssueSortUpdated, github.SortDirectionAsc, []int{3, 2, 4, 1}},
+		{"comments desc", github.IssueSortComments, "", []int{2, 4, 1, 3}},
+		{"comments asc", github.IssueSortComments, github.SortDirectionAsc, []int{3, 1, 2, 4}},
+		// age は作成からの経過時間のため、降順は古い順
+		{"age desc is oldest first", github.IssueSortAge, github.SortDirectionDesc, []int{1, 2, 4, 3}},
+		{"age asc is newest first", github.IssueSortAge, github.SortDirectionAsc, []int{3, 2, 4, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := append([]github.Issue(nil), issues...)
+			SortIssues(sorted, tt.key, tt.direction)
+			if got := issueNumbers(sorted); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortIssues(%q, %q) = %v, want %v", tt.key, tt.direction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupIssues(t *testing.T) {
+	issues := []github.Issue{
+		{
+			Number:     1,
+			Repository: &github.Repository{FullName: "acme/web"},
+			Labels:     []github.Label{{Name: "bug"}, {Name: "p1"}},
+			Assignees:  []github.User{{Login: "octocat"}, {Login: "hubot"}},
+			Milestone:  &github.Milestone{Title: "v2.0"},
+		},
+		{
+			Number:     2,
+			Repository: &github.Repository{FullName: "acme/api"},
+			Labels:     []github.Label{{Name: "Docs"}},
+		},
+		{
+			Number:     3,
+			Repository: &github.Repository{FullName: "acme/api"},
+			Labels:     []github.Label{{Name: "bug"}},
+			Assignees:  []github.User{{Login: "octocat"}},
+			Milestone:  &github.Milestone{Title: "v1.1"},
+		},
+		{Number: 4},
+	}
+
+	tests := []struct {
+		name      string
+		by        GroupBy
+		want      map[string][]int // グループ名 → グループ内の Issue 番号
+		wantOrder []string         // グループの順序（該当なしは空文字列）
+	}{
+		{
+			name:      "repository",
+			by:        GroupByRepository,
+			want:      map[string][]int{"acme/api": {2, 3}, "acme/web": {1}, "": {4}},
+			wantOrder: []string{"acme/api", "acme/web", ""},
+		},
+		{
+			// ラベルが複数ある Issue はそれぞれのグループに含め、名前は大文字・小文字を区別せずに並べる
+			name:      "label",
+			by:        GroupByLabel,
+			want:      map[string][]int{"bug": {1, 3}, "Docs": {2}, "p1": {1}, "": {4}},
+			wantOrder: []string{"bug", "Docs", "p1", ""},
+		},
+		{
+			name:      "assignee",
+			by:        GroupByAssignee,
+			want:      map[string][]int{"hubot": {1}, "octocat": {1, 3}, "": {2, 4}},
+			wantOrder: []string{"hubot", "octocat", ""},
+		},
+		{
+			name:      "milestone",
+			by:        GroupByMilestone,
+			want:      map[string][]int{"v1.1": {3}, "v2.0": {1}, "": {2, 4}},
+			wantOrder: []string{"v1.1", "v2.0", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := GroupIssues(issues, tt.by)
+
+			var order []string
+			got := make(map[string][]int)
+			for _, group := range groups {
+				if group.By != tt.by {
+					t.Errorf("group %q By = %q, want %q", group.Name, group.By, tt.by)
+				}
+				order = append(order, group.Name)
+				got[group.Name] = issueNumbers(group.Issues)
+			}
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("group order = %q, want %q", order, tt.wantOrder)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groups = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if groups := GroupIssues(issues, GroupByNone); groups != nil {
+		t.Errorf("GroupIssues(GroupByNone) = %+v, want nil", groups)
+	}
+}
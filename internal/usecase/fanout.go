@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+const (
+	// DefaultFetchConcurrency は複数リポジトリ取得時の既定の同時実行数です
+	DefaultFetchConcurrency = 5
+
+	// lowRateLimitThreshold を残り回数が下回るとリクエストの投入間隔を空けます
+	lowRateLimitThreshold = 100
+	// maxThrottleDelay は1リクエストあたりの最大待機時間です
+	maxThrottleDelay = 5 * time.Second
+)
+
+// errRateLimitExhausted はRate Limitを使い切り、リセットまで待てない場合に返されます
+var errRateLimitExhausted = errors.New("rate limit exhausted")
+
+// rateLimitTracker は並行リクエスト間で最新のRate Limit情報をリソース (core / search など) ごとに共有します
+type rateLimitTracker struct {
+	mu     sync.Mutex
+	latest map[string]*github.RateLimitInfo
+}
+
+func newRateLimitTracker(initial *github.RateLimitInfo) *rateLimitTracker {
+	t := &rateLimitTracker{latest: make(map[string]*github.RateLimitInfo)}
+	t.update(initial)
+	return t
+}
+
+// update はレスポンスのRate Limit情報を反映します。
+// レスポンスの到着順は前後するため、同じリセット時刻では残り回数の少ない方を採用します。
+func (t *rateLimitTracker) update(rateLimit *github.RateLimitInfo) {
+	if rateLimit == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	latest := t.latest[rateLimit.Resource]
+	if latest == nil ||
+		rateLimit.ResetAt.After(latest.ResetAt) ||
+		(rateLimit.ResetAt.Equal(latest.ResetAt) && rateLimit.Remaining < latest.Remaining) {
+		t.latest[rateLimit.Resource] = rateLimit
+	}
+}
+
+// snapshot は表示用に、残り回数の割合が最も少ないリソースのRate Limit情報を返します
+func (t *rateLimitTracker) snapshot() *github.RateLimitInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var lowest *github.RateLimitInfo
+	for _, rateLimit := range t.latest {
+		if lowest == nil || remainingRatio(rateLimit) < remainingRatio(lowest) {
+			lowest = rateLimit
+		}
+	}
+	return lowest
+}
+
+// core は REST API (core リソース) のRate Limit情報を返します
+func (t *rateLimitTracker) core() *github.RateLimitInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if rateLimit, ok := t.latest[github.RateLimitResourceCore]; ok {
+		return rateLimit
+	}
+	// X-RateLimit-Resource を返さない環境ではリソース不明として扱う
+	return t.latest[""]
+}
+
+// remainingRatio は上限に対する残り回数の割合を返します（上限が不明な場合は残り回数で比較します）
+func remainingRatio(rateLimit *github.RateLimitInfo) float64 {
+	if rateLimit.Limit <= 0 {
+		return float64(rateLimit.Remaining)
+	}
+	return float64(rateLimit.Remaining) / float64(rateLimit.Limit)
+}
+
+// throttleDelay は REST API の残り回数に応じた次のリクエストまでの待機時間を返します。
+// 残り回数が 0 の場合は errRateLimitExhausted を返します。
+func (t *rateLimitTracker) throttleDelay(now time.Time) (time.Duration, error) {
+	rateLimit := t.core()
+	if rateLimit == nil || rateLimit.ResetAt.IsZero() || rateLimit.Remaining >= lowRateLimitThreshold {
+		return 0, nil
+	}
+
+	untilReset := rateLimit.ResetAt.Sub(now)
+	if untilReset <= 0 {
+		return 0, nil
+	}
+	if rateLimit.Remaining <= 0 {
+		return 0, errRateLimitExhausted
+	}
+
+	// リセットまでの残り時間を残り回数で按分してリクエストを分散させる
+	delay := untilReset / time.Duration(rateLimit.Remaining)
+	if delay > maxThrottleDelay {
+		delay = maxThrottleDelay
+	}
+	return delay, nil
+}
+
+// wait は残り回数が少ない場合に次のリクエストまで待機します
+func (t *rateLimitTracker) wait(ctx context.Context) error {
+	delay, err := t.throttleDelay(time.Now())
+	if err != nil || delay == 0 {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// fanOut は items を最大 concurrency 件ずつ並行して処理し、入力と同じ順序で結果を返します。
+// ctx の終了や Rate Limit の枯渇により処理されなかった要素は done が false になります。
+func fanOut[T, R any](ctx context.Context, items []T, concurrency int, tracker *rateLimitTracker, fn func(ctx context.Context, item T) R) (results []R, done []bool) {
+	results = make([]R, len(items))
+	done = make([]bool, len(items))
+	if len(items) == 0 {
+		return results, done
+	}
+
+	if concurrency <= 0 {
+		concurrency = DefaultFetchConcurrency
+	}
+	if concurrency > len(items) {
+		concurrency = len(items)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = fn(ctx, items[idx])
+				done[idx] = true
+			}
+		}()
+	}
+
+dispatch:
+	for idx := range items {
+		if err := tracker.wait(ctx); err != nil {
+			break dispatch
+		}
+		select {
+		case jobs <- idx:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, done
+}
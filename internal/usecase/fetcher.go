@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// Issue 取得に使う GitHub API
+const (
+	// IssuesBackendREST は REST API でリポジトリごと・ページごとに Issue を取得します
+	IssuesBackendREST = "rest"
+	// IssuesBackendGraphQL は GraphQL API で複数リポジトリの Issue と関連情報をまとめて取得します
+	IssuesBackendGraphQL = "graphql"
+)
+
+// repositoryIssuesFetcher はリポジトリ単位の Issue 取得方法（REST / GraphQL）を抽象化します
+type repositoryIssuesFetcher interface {
+	// fetchRepositories は targets の Issue を取得し、targets と同じ順序で結果を返します。
+	// ctx の終了などで取得できなかったリポジトリは done が false になります。
+	fetchRepositories(ctx context.Context, targets []github.Repository, tracker *rateLimitTracker) (outcomes []repositoryIssuesOutcome, done []bool)
+}
+
+// repositoryIssuesOutcome は1リポジトリ分のIssue取得結果です
+type repositoryIssuesOutcome struct {
+	issues []github.Issue
+	err    error
+}
+
+// restIssuesFetcher は REST API でリポジトリを並行して取得します
+type restIssuesFetcher struct {
+	client      *github.Client
+	concurrency int
+	filter      github.IssueFilter
+}
+
+func (f *restIssuesFetcher) fetchRepositories(ctx context.Context, targets []github.Repository, tracker *rateLimitTracker) ([]repositoryIssuesOutcome, []bool) {
+	return fanOut(ctx, targets, f.concurrency, tracker, func(ctx context.Context, repo github.Repository) repositoryIssuesOutcome {
+		parts := splitRepoFullName(repo.FullName)
+		issues, rl, err := f.client.GetAllRepositoryIssues(ctx, parts[0], parts[1], f.filter)
+		tracker.update(rl)
+
+		// Add repository info to each issue
+		fillRepository(issues, repo.FullName)
+		return repositoryIssuesOutcome{issues: issues, err: err}
+	})
+}
+
+// graphQLIssuesFetcher は GraphQL API で複数リポジトリをまとめて取得します
+type graphQLIssuesFetcher struct {
+	client   *github.GraphQLClient
+	itemType github.ItemType
+	fields   github.GraphQLIssueFields
+	filter   github.IssueFilter
+}
+
+func (f *graphQLIssuesFetcher) fetchRepositories(ctx context.Context, targets []github.Repository, tracker *rateLimitTracker) ([]repositoryIssuesOutcome, []bool) {
+	names := make([]string, len(targets))
+	for idx, repo := range targets {
+		names[idx] = repo.FullName
+	}
+
+	outcomes := make([]repositoryIssuesOutcome, len(targets))
+	done := make([]bool, len(targets))
+
+	results, rl, err := f.client.GetRepositoriesIssues(ctx, names, f.itemType, f.fields, f.filter)
+	tracker.update(rl)
+
+	for idx, result := range results {
+		outcomes[idx] = repositoryIssuesOutcome{issues: result.Issues, err: result.Err}
+		switch {
+		case err == nil:
+			done[idx] = true
+		case len(result.Issues) > 0:
+			// 途中まで取得できたリポジトリは打ち切りとして扱う
+			outcomes[idx].err = err
+			done[idx] = true
+		}
+	}
+	return outcomes, done
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// IssueActions は Issue の操作パネルに表示する内容です
+type IssueActions struct {
+	Issue *github.Issue
+	// Labels はリポジトリのラベルです（取得できなかった場合は nil）
+	Labels []github.Label
+}
+
+// IssueRef は操作対象の Issue (または Pull Request) です
+type IssueRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// actionClient はユーザー自身のトークンで認証したクライアントを返します。
+// 操作は GitHub 上でユーザー本人の操作として記録されるため、GitHub App のトークンでは実行しません。
+func (u *IssuesUsecase) actionClient(ctx context.Context, guildID, userID string) (*github.Client, string, error) {
+	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if usesInstallationToken(setting) {
+		return nil, "", ErrPersonalTokenRequired
+	}
+	return u.newClient(setting, token), setting.TokenLogin, nil
+}
+
+// GetIssueActions は Issue の最新の状態とリポジトリのラベルを取得します
+func (u *IssuesUsecase) GetIssueActions(ctx context.Context, guildID, userID string, ref IssueRef) (*IssueActions, error) {
+	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return loadIssueActions(ctx, u.newClient(setting, token), ref)
+}
+
+// loadIssueActions は Issue とリポジトリのラベルを取得します。ラベルを取得できない場合はラベルなしで返します。
+func loadIssueActions(ctx context.Context, client *github.Client, ref IssueRef) (*IssueActions, error) {
+	issue, _, err := client.GetIssue(ctx, ref.Owner, ref.Repo, ref.Number)
+	if err != nil {
+		return nil, err
+	}
+	labels, _, err := client.GetRepositoryLabels(ctx, ref.Owner, ref.Repo)
+	if err != nil {
+		labels = nil
+	}
+	return &IssueActions{Issue: issue, Labels: labels}, nil
+}
+
+// CloseIssue は Issue をクローズします
+func (u *IssuesUsecase) CloseIssue(ctx context.Context, guildID, userID string, ref IssueRef) (*IssueActions, error) {
+	return u.updateIssueState(ctx, guildID, userID, ref, github.IssueStateValueClosed)
+}
+
+// ReopenIssue はクローズされた Issue を再オープンします
+func (u *IssuesUsecase) ReopenIssue(ctx context.Context, guildID, userID string, ref IssueRef) (*IssueActions, error) {
+	return u.updateIssueState(ctx, guildID, userID, ref, github.IssueStateValueOpen)
+}
+
+func (u *IssuesUsecase) updateIssueState(ctx context.Context, guildID, userID string, ref IssueRef, state string) (*IssueActions, error) {
+	client, _, err := u.actionClient(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := client.UpdateIssueState(ctx, ref.Owner, ref.Repo, ref.Number, state); err != nil {
+		return nil, err
+	}
+	return loadIssueActions(ctx, client, ref)
+}
+
+// AssignIssueToMe はトークンの認証ユーザーを Issue の担当者に追加します
+func (u *IssuesUsecase) AssignIssueToMe(ctx context.Context, guildID, userID string, ref IssueRef) (*IssueActions, error) {
+	client, login, err := u.actionClient(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// 認証ユーザーを記録する前に登録されたトークンは、ここで取得する
+	if login == "" {
+		info, err := client.ValidateToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		login = info.Login
+	}
+	if _, _, err := client.AddAssignees(ctx, ref.Owner, ref.Repo, ref.Number, []string{login}); err != nil {
+		return nil, err
+	}
+	return loadIssueActions(ctx, client, ref)
+}
+
+// AddIssueLabel は Issue にラベルを追加します
+func (u *IssuesUsecase) AddIssueLabel(ctx context.Context, guildID, userID string, ref IssueRef, label string) (*IssueActions, error) {
+	client, _, err := u.actionClient(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := client.AddLabels(ctx, ref.Owner, ref.Repo, ref.Number, []string{label}); err != nil {
+		return nil, err
+	}
+	return loadIssueActions(ctx, client, ref)
+}
+
+// RemoveIssueLabel は Issue からラベルを外します
+func (u *IssuesUsecase) RemoveIssueLabel(ctx context.Context, guildID, userID string, ref IssueRef, label string) (*IssueActions, error) {
+	client, _, err := u.actionClient(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := client.RemoveLabel(ctx, ref.Owner, ref.Repo, ref.Number, label); err != nil {
+		return nil, err
+	}
+	return loadIssueActions(ctx, client, ref)
+}
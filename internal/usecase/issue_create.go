@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// ListIssueTemplates はリポジトリの Markdown 形式の Issue テンプレートのファイル名を返します
+func (u *IssuesUsecase) ListIssueTemplates(ctx context.Context, guildID, userID, owner, repo string) ([]string, error) {
+	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	names, _, err := u.newClient(setting, token).GetIssueTemplateNames(ctx, owner, repo)
+	return names, err
+}
+
+// GetIssueTemplate はリポジトリの Issue テンプレートを取得します
+func (u *IssuesUsecase) GetIssueTemplate(ctx context.Context, guildID, userID, owner, repo, fileName string) (*github.IssueTemplate, error) {
+	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	template, _, err := u.newClient(setting, token).GetIssueTemplate(ctx, owner, repo, fileName)
+	return template, err
+}
+
+// CreateIssue はユーザー自身のトークンでリポジトリに Issue を作成します
+func (u *IssuesUsecase) CreateIssue(ctx context.Context, guildID, userID, owner, repo string, newIssue github.NewIssue) (*github.Issue, error) {
+	client, _, err := u.actionClient(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	issue, _, err := client.CreateIssue(ctx, owner, repo, newIssue)
+	return issue, err
+}
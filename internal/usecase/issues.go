@@ -15,17 +15,74 @@ import (
 type IssuesUsecase struct {
 	repo   repository.UserSettingRepository
 	crypto *crypto.AESCrypto
+	config IssuesConfig
 }
 
-func NewIssuesUsecase(repo repository.UserSettingRepository, crypto *crypto.AESCrypto) *IssuesUsecase {
+// IssuesConfig は IssuesUsecase の動作設定です
+type IssuesConfig struct {
+	GitHubBaseURL string // 既定の GitHub API ベースURL（空の場合は github.com）
+	Concurrency   int    // 複数リポジトリ取得時の同時実行数（0 以下の場合は DefaultFetchConcurrency）
+	Strategy      string // owner / all 指定時の取得方式（IssuesStrategySearch または IssuesStrategyCrawl）
+	Backend       string // リポジトリ単位の取得に使う API（IssuesBackendREST または IssuesBackendGraphQL）
+
+	// ResponseCache は REST API の条件付きリクエストに使うキャッシュです（nil の場合はキャッシュしない）
+	ResponseCache github.ResponseCache
+
+	// MaxPages は1コマンドで取得する REST API のページ数の上限です（0 以下の場合は無制限）
+	MaxPages int
+
+	// RateLimits はトークンごとの Rate Limit の状態です（nil の場合はコマンドごとに保持する）
+	RateLimits *RateLimitStates
+
+	// AppInstallations は個人のトークンがない場合に使う GitHub App のインストールです（nil の場合はフォールバックしない）
+	AppInstallations *AppInstallationUsecase
+
+	// Login は /login で取得したトークンを期限切れ前に更新します（nil の場合は更新しない）
+	Login *LoginUsecase
+}
+
+// withPageBudget は1コマンド分のページ数の上限を ctx に設定します
+func (c IssuesConfig) withPageBudget(ctx context.Context) context.Context {
+	if c.MaxPages <= 0 {
+		return ctx
+	}
+	return github.WithPageBudget(ctx, github.NewPageBudget(c.MaxPages))
+}
+
+// clientOptions は token を使う REST・GraphQL クライアントの生成オプションを返します
+func (c IssuesConfig) clientOptions(token string) []github.Option {
+	var opts []github.Option
+	if c.ResponseCache != nil {
+		opts = append(opts, github.WithCache(c.ResponseCache))
+	}
+	if c.RateLimits != nil {
+		opts = append(opts, github.WithRateLimitState(c.RateLimits.forToken(token)))
+	}
+	return opts
+}
+
+func NewIssuesUsecase(repo repository.UserSettingRepository, crypto *crypto.AESCrypto, config IssuesConfig) *IssuesUsecase {
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultFetchConcurrency
+	}
+	if config.Strategy != IssuesStrategyCrawl {
+		config.Strategy = IssuesStrategySearch
+	}
 	return &IssuesUsecase{
 		repo:   repo,
 		crypto: crypto,
+		config: config,
 	}
 }
 
 var ErrTokenNotFound = errors.New("token not registered")
 
+// ErrTokenExpired は登録済みのトークンの有効期限が切れている場合に返されます
+var ErrTokenExpired = errors.New("token expired")
+
+// ErrTokenRevoked は定期確認でトークンの取り消し・期限切れを検知済みの場合に返されます
+var ErrTokenRevoked = errors.New("token revoked")
+
 // RepositoryError はリポジトリ処理中に発生したエラーを保持します
 type RepositoryError struct {
 	RepositoryName string
@@ -34,170 +91,326 @@ type RepositoryError struct {
 
 // IssuesResult はIssue取得結果とエラー情報を保持します
 type IssuesResult struct {
-	Issues       []github.Issue
-	RateLimit    *github.RateLimitInfo
-	FailedRepos  []RepositoryError
+	Issues      []github.Issue
+	RateLimit   *github.RateLimitInfo
+	FailedRepos []RepositoryError
+	Truncated   bool         // タイムアウトやキャンセルにより途中までの結果である場合に true
+	Groups      []IssueGroup // IssuesOptions.GroupBy でまとめた Issue（指定しない場合は nil）
+}
+
+// IssuesOptions は Issue 取得時の絞り込み条件です
+type IssuesOptions struct {
+	Type    github.ItemType    // Issue / Pull Request / 両方（空の場合は両方）
+	Filter  github.IssueFilter // 状態・ラベル・担当者などの絞り込み条件と並び順（ゼロ値はオープンな Issue すべて）
+	GroupBy GroupBy            // 結果をまとめる単位（空の場合はまとめない）
+}
+
+// itemType は対象の種類を返します。未指定の場合は両方を対象にします。
+func (o IssuesOptions) itemType() github.ItemType {
+	if o.Type == "" {
+		return github.ItemTypeBoth
+	}
+	return o.Type
+}
+
+// apply は取得結果に絞り込み条件を適用します。
+// API で指定できなかった条件もここで適用し、複数リポジトリの結果を結合した後の並び順を揃えます。
+func (o IssuesOptions) apply(result *IssuesResult, err error) (*IssuesResult, error) {
+	if result == nil {
+		return result, err
+	}
+
+	itemType := o.itemType()
+	filtered := make([]github.Issue, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		if itemType.Matches(issue) && o.Filter.Matches(issue) {
+			filtered = append(filtered, issue)
+		}
+	}
+	if o.Filter.Sorted() {
+		SortIssues(filtered, o.Filter.Sort, o.Filter.Direction)
+	}
+	result.Issues = filtered
+	result.Groups = GroupIssues(filtered, o.GroupBy)
+	return result, err
+}
+
+// isTruncationError はタイムアウト・キャンセル・ページ数の上限による打ち切りのエラーかどうかを判定します
+func isTruncationError(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.Is(err, github.ErrPageBudgetExceeded)
+}
+
+// partialResult は取得途中で打ち切られた場合に、取得済みの Issue があれば途中結果として返します
+func partialResult(issues []github.Issue, rateLimit *github.RateLimitInfo, err error) (*IssuesResult, error) {
+	result := &IssuesResult{Issues: issues, RateLimit: rateLimit}
+	if err == nil {
+		return result, nil
+	}
+	if isTruncationError(err) && len(issues) > 0 {
+		result.Truncated = true
+		return result, nil
+	}
+	return result, err
 }
 
 // getSettingAndToken はユーザー設定を取得し、トークンを復号化して両方を返します
 func (u *IssuesUsecase) getSettingAndToken(ctx context.Context, guildID, userID string) (*entity.UserSetting, string, error) {
-	setting, err := u.repo.FindByGuildAndUser(ctx, guildID, userID)
+	return loadSettingAndToken(ctx, u.repo, u.crypto, u.config, guildID, userID)
+}
+
+// loadSettingAndToken はユーザー設定を取得し、トークンを復号化して両方を返します。
+// 個人のトークンが登録されていない場合は、サーバーに紐付いた GitHub App のインストールアクセストークンを返します。
+// /login で取得したトークンの期限が近い場合は、更新したトークンを返します。
+func loadSettingAndToken(ctx context.Context, repo repository.UserSettingRepository, crypto *crypto.AESCrypto, config IssuesConfig, guildID, userID string) (*entity.UserSetting, string, error) {
+	setting, err := repo.FindByGuildAndUser(ctx, guildID, userID)
 	if err != nil {
 		return nil, "", err
 	}
-	if setting == nil {
-		return nil, "", ErrTokenNotFound
-	}
 
 	// トークンが設定されていない場合
-	if setting.EncryptedToken == "" {
-		return nil, "", ErrTokenNotFound
+	if setting == nil || setting.EncryptedToken == "" {
+		token, err := config.AppInstallations.installationToken(ctx, guildID)
+		if err != nil {
+			return nil, "", err
+		}
+		if setting == nil {
+			setting = &entity.UserSetting{GuildID: guildID, UserID: userID}
+		}
+		return setting, token, nil
 	}
 
-	token, err := u.crypto.Decrypt(setting.EncryptedToken)
+	if !setting.TokenInvalidAt.IsZero() {
+		return nil, "", ErrTokenRevoked
+	}
+
+	token, err := crypto.Decrypt(setting.EncryptedToken)
 	if err != nil {
 		// 復号化エラーもトークン関連のエラーとして扱う
 		return nil, "", ErrTokenNotFound
 	}
 
+	token, err = config.Login.refreshIfExpired(ctx, setting, token)
+	if err != nil {
+		return nil, "", err
+	}
 	return setting, token, nil
 }
 
-// getDecryptedToken はユーザー設定を取得し、トークンを復号化して返します
-func (u *IssuesUsecase) getDecryptedToken(ctx context.Context, guildID, userID string) (string, error) {
-	_, token, err := u.getSettingAndToken(ctx, guildID, userID)
-	return token, err
+// usesInstallationToken は GitHub App のインストールアクセストークンで認証しているかを返します
+func usesInstallationToken(setting *entity.UserSetting) bool {
+	return setting.EncryptedToken == ""
+}
+
+// listAccessibleRepositories は認証情報でアクセスできる全リポジトリを取得します
+func listAccessibleRepositories(ctx context.Context, client *github.Client, setting *entity.UserSetting) ([]github.Repository, *github.RateLimitInfo, error) {
+	if usesInstallationToken(setting) {
+		// インストールアクセストークンでは /user/repos を利用できない
+		return client.GetAllInstallationRepositories(ctx)
+	}
+	return client.GetAllUserRepositories(ctx)
+}
+
+// newClient はユーザー設定のベースURL（未設定の場合は既定値）を使ってGitHubクライアントを生成します
+func (u *IssuesUsecase) newClient(setting *entity.UserSetting, token string) *github.Client {
+	return github.NewClient(token, resolveBaseURL(setting, u.config.GitHubBaseURL), u.config.clientOptions(token)...)
+}
+
+// newFetcher は設定されたバックエンドに応じたリポジトリ単位の Issue 取得方法を返します。
+// GraphQL の Pull Request はメンションで絞り込めないため、その場合は REST を使います。
+func (u *IssuesUsecase) newFetcher(setting *entity.UserSetting, token string, opts IssuesOptions) repositoryIssuesFetcher {
+	mentionedPullRequests := opts.Filter.Mentioned != "" && opts.itemType().IncludesPullRequests()
+	if u.config.Backend == IssuesBackendGraphQL && !mentionedPullRequests {
+		return &graphQLIssuesFetcher{
+			client:   github.NewGraphQLClient(token, resolveBaseURL(setting, u.config.GitHubBaseURL), u.config.clientOptions(token)...),
+			itemType: opts.itemType(),
+			fields:   github.AllGraphQLIssueFields,
+			filter:   opts.Filter,
+		}
+	}
+	return &restIssuesFetcher{
+		client:      u.newClient(setting, token),
+		concurrency: u.config.Concurrency,
+		filter:      opts.Filter,
+	}
+}
+
+// resolveBaseURL はユーザー設定のベースURLを優先し、未設定の場合は既定値を返します
+func resolveBaseURL(setting *entity.UserSetting, defaultBaseURL string) string {
+	if setting != nil && setting.GitHubBaseURL != "" {
+		return setting.GitHubBaseURL
+	}
+	return defaultBaseURL
 }
 
-func (u *IssuesUsecase) GetAssignedIssues(ctx context.Context, guildID, userID string) ([]github.Issue, *github.RateLimitInfo, error) {
+func (u *IssuesUsecase) GetAssignedIssues(ctx context.Context, guildID, userID string, opts IssuesOptions) (*IssuesResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
 	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	client := github.NewClient(token)
-	issues, rateLimit, err := client.GetAllAssignedIssues()
-	if err != nil {
-		return nil, rateLimit, err
+	// 割り当ては GitHub ユーザーに紐付くため、GitHub App のトークンでは取得できない
+	if usesInstallationToken(setting) {
+		return nil, ErrPersonalTokenRequired
 	}
 
+	client := u.newClient(setting, token)
+	issues, rateLimit, err := client.GetAllAssignedIssues(ctx, opts.Filter)
+
 	// Apply excluded repositories filter for assign command
-	filteredIssues := u.filterExcludedRepositories(issues, setting.ExcludedAssignRepositories)
-	return filteredIssues, rateLimit, nil
+	filteredIssues := filterExcludedRepositories(issues, setting.ExcludedAssignRepositories)
+	return opts.apply(partialResult(filteredIssues, rateLimit, err))
 }
 
-func (u *IssuesUsecase) GetRepositoryIssues(ctx context.Context, guildID, userID, owner, repo string) ([]github.Issue, *github.RateLimitInfo, error) {
-	token, err := u.getDecryptedToken(ctx, guildID, userID)
+func (u *IssuesUsecase) GetRepositoryIssues(ctx context.Context, guildID, userID, owner, repo string, opts IssuesOptions) (*IssuesResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
+	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	client := github.NewClient(token)
-	issues, rateLimit, err := client.GetAllRepositoryIssues(owner, repo)
-	if issues != nil {
-		fullName := fmt.Sprintf("%s/%s", owner, repo)
-		for idx := range issues {
-			if issues[idx].Repository == nil {
-				issues[idx].Repository = &github.Repository{FullName: fullName}
-			}
+	fullName := fmt.Sprintf("%s/%s", owner, repo)
+	tracker := newRateLimitTracker(nil)
+	outcomes, done := u.newFetcher(setting, token, opts).fetchRepositories(ctx, []github.Repository{{FullName: fullName}}, tracker)
+	if !done[0] {
+		err := ctx.Err()
+		if err == nil {
+			err = errRateLimitExhausted
 		}
+		return &IssuesResult{RateLimit: tracker.snapshot()}, err
 	}
-	return issues, rateLimit, err
+	return opts.apply(partialResult(outcomes[0].issues, tracker.snapshot(), outcomes[0].err))
 }
 
-func (u *IssuesUsecase) GetAllRepositoriesIssues(ctx context.Context, guildID, userID string) (*IssuesResult, error) {
+func (u *IssuesUsecase) GetAllRepositoriesIssues(ctx context.Context, guildID, userID string, opts IssuesOptions) (*IssuesResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
 	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	client := github.NewClient(token)
+	client := u.newClient(setting, token)
 
 	// Get all user repositories
-	repos, rateLimit, err := client.GetAllUserRepositories()
+	repos, rateLimit, err := listAccessibleRepositories(ctx, client, setting)
 	if err != nil {
 		return &IssuesResult{RateLimit: rateLimit}, err
 	}
 
+	if u.config.Strategy == IssuesStrategySearch {
+		result, err := searchRepositoriesIssues(ctx, client, repos, setting.ExcludedIssuesRepositories, opts)
+		if !errors.Is(err, github.ErrSearchResultLimitExceeded) {
+			return opts.apply(result, err)
+		}
+		// 検索結果が上限を超える場合はリポジトリごとの取得にフォールバックする
+	}
+
 	// Fetch issues from repositories with exclusion filtering and error collection
-	result := fetchIssuesFromRepositories(client, repos, setting.ExcludedIssuesRepositories, rateLimit)
-	return result, nil
+	result := fetchIssuesFromRepositories(ctx, u.newFetcher(setting, token, opts), repos, setting.ExcludedIssuesRepositories, rateLimit)
+	return opts.apply(result, nil)
 }
 
-func (u *IssuesUsecase) GetUserIssues(ctx context.Context, guildID, userID, username string) (*IssuesResult, error) {
+func (u *IssuesUsecase) GetUserIssues(ctx context.Context, guildID, userID, username string, opts IssuesOptions) (*IssuesResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
 	setting, token, err := u.getSettingAndToken(ctx, guildID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	client := github.NewClient(token)
+	client := u.newClient(setting, token)
+
+	if u.config.Strategy == IssuesStrategySearch {
+		result, err := searchOwnerIssues(ctx, client, username, setting.ExcludedIssuesRepositories, opts)
+		if !errors.Is(err, github.ErrSearchResultLimitExceeded) {
+			return opts.apply(result, err)
+		}
+		// 検索結果が上限を超える場合はリポジトリごとの取得にフォールバックする
+	}
 
 	// Get all repositories for the specific user
-	repos, rateLimit, err := client.GetAllSpecificUserRepositories(username)
+	repos, rateLimit, err := client.GetAllSpecificUserRepositories(ctx, username)
 	if err != nil {
 		return &IssuesResult{RateLimit: rateLimit}, err
 	}
 
 	// Fetch issues from repositories with exclusion filtering and error collection
-	result := fetchIssuesFromRepositories(client, repos, setting.ExcludedIssuesRepositories, rateLimit)
-	return result, nil
+	result := fetchIssuesFromRepositories(ctx, u.newFetcher(setting, token, opts), repos, setting.ExcludedIssuesRepositories, rateLimit)
+	return opts.apply(result, nil)
 }
 
 func splitRepoFullName(fullName string) []string {
 	return strings.SplitN(fullName, "/", 2)
 }
 
-// fetchIssuesFromRepositories は複数のリポジトリからIssueを取得する共通ロジックです
-func fetchIssuesFromRepositories(client *github.Client, repos []github.Repository, excludedRepos []string, initialRateLimit *github.RateLimitInfo) *IssuesResult {
+// fetchIssuesFromRepositories は複数のリポジトリからIssueを取得する共通ロジックです。
+// 取得は fetcher（REST / GraphQL）に委ね、結果はリポジトリの並び順で結合します。
+// ctx の終了・Rate Limit の枯渇・ページ数の上限で残りのリポジトリを取得できなかった場合は Truncated としてマークします。
+func fetchIssuesFromRepositories(ctx context.Context, fetcher repositoryIssuesFetcher, repos []github.Repository, excludedRepos []string, initialRateLimit *github.RateLimitInfo) *IssuesResult {
 	result := &IssuesResult{
 		Issues:      make([]github.Issue, 0),
 		RateLimit:   initialRateLimit,
 		FailedRepos: make([]RepositoryError, 0),
 	}
 
+	targets := make([]github.Repository, 0, len(repos))
 	for _, repo := range repos {
 		// Skip excluded repositories using pattern matching
 		if isRepositoryExcluded(repo.FullName, excludedRepos) {
 			continue
 		}
-
-		// Extract owner and repo name
-		parts := splitRepoFullName(repo.FullName)
-		if len(parts) != 2 {
+		if len(splitRepoFullName(repo.FullName)) != 2 {
 			continue
 		}
+		targets = append(targets, repo)
+	}
 
-		owner := parts[0]
-		repoName := parts[1]
+	tracker := newRateLimitTracker(initialRateLimit)
+	outcomes, done := fetcher.fetchRepositories(ctx, targets, tracker)
 
-		// Get issues for this repository
-		issues, rl, err := client.GetAllRepositoryIssues(owner, repoName)
-		if err != nil {
+	for idx, outcome := range outcomes {
+		if !done[idx] {
+			result.Truncated = true
+			continue
+		}
+
+		if outcome.err != nil && isTruncationError(outcome.err) {
+			// 打ち切られた場合は取得済みの分だけを結果に含める
+			result.Issues = append(result.Issues, outcome.issues...)
+			result.Truncated = true
+			continue
+		}
+		if outcome.err != nil {
 			// Collect error instead of silently skipping
 			result.FailedRepos = append(result.FailedRepos, RepositoryError{
-				RepositoryName: repo.FullName,
-				Error:          err,
+				RepositoryName: targets[idx].FullName,
+				Error:          outcome.err,
 			})
 			continue
 		}
 
-		if rl != nil {
-			result.RateLimit = rl
-		}
-
-		// Add repository info to each issue
-		for idx := range issues {
-			if issues[idx].Repository == nil {
-				issues[idx].Repository = &github.Repository{FullName: repo.FullName}
-			}
-		}
+		result.Issues = append(result.Issues, outcome.issues...)
+	}
 
-		result.Issues = append(result.Issues, issues...)
+	if rateLimit := tracker.snapshot(); rateLimit != nil {
+		result.RateLimit = rateLimit
 	}
 	return result
 }
 
-func (u *IssuesUsecase) filterExcludedRepositories(issues []github.Issue, excludedRepos []string) []github.Issue {
+// fillRepository はリポジトリ情報を持たない Issue に fullName を設定します
+func fillRepository(issues []github.Issue, fullName string) {
+	for idx := range issues {
+		if issues[idx].Repository == nil {
+			issues[idx].Repository = &github.Repository{FullName: fullName}
+		}
+	}
+}
+
+func filterExcludedRepositories(issues []github.Issue, excludedRepos []string) []github.Issue {
 	if len(excludedRepos) == 0 {
 		return issues
 	}
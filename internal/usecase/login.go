@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// ErrLoginNotConfigured は OAuth のクライアントIDが Bot に設定されていない場合に返されます
+var ErrLoginNotConfigured = errors.New("oauth login is not configured")
+
+// ErrLoginExpired は /login で取得したトークンの期限が切れ、更新もできない場合に返されます
+var ErrLoginExpired = errors.New("oauth login expired")
+
+// DefaultLoginScopes は OAuth App で /login する場合に要求する既定のスコープです
+var DefaultLoginScopes = []string{"repo", "read:org"}
+
+// oauthTokenRefreshMargin はアクセストークンを期限前に更新する余裕です
+const oauthTokenRefreshMargin = 5 * time.Minute
+
+// DeviceFlowAuthenticator は OAuth のデバイス認可フローとトークンの更新を行います（github.OAuthApp が実装します）
+type DeviceFlowAuthenticator interface {
+	RequestDeviceCode(ctx context.Context, scopes []string) (*github.DeviceCode, error)
+	PollAccessToken(ctx context.Context, code *github.DeviceCode) (*github.OAuthToken, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*github.OAuthToken, error)
+}
+
+// LoginUsecase は /login によるデバイス認可フローと、取得したトークンの自動更新を扱います
+type LoginUsecase struct {
+	settings *SettingUsecase
+	oauth    DeviceFlowAuthenticator
+	scopes   []string
+
+	// refreshMu はトークンの更新を直列化します（リフレッシュトークンは1回しか使えないため）
+	refreshMu sync.Mutex
+}
+
+// NewLoginUsecase は LoginUsecase を生成します。
+// oauth が nil の場合（OAuth 未設定）は /login を利用できず、トークンの更新も行いません。
+func NewLoginUsecase(settings *SettingUsecase, oauth DeviceFlowAuthenticator, scopes []string) *LoginUsecase {
+	if len(scopes) == 0 {
+		scopes = DefaultLoginScopes
+	}
+	return &LoginUsecase{
+		settings: settings,
+		oauth:    oauth,
+		scopes:   scopes,
+	}
+}
+
+// Enabled は OAuth のクライアントIDが設定されているかを返します
+func (u *LoginUsecase) Enabled() bool {
+	return u != nil && u.oauth != nil
+}
+
+// StartLogin はデバイス認可フローを開始し、ユーザーに表示するコードを返します
+func (u *LoginUsecase) StartLogin(ctx context.Context) (*github.DeviceCode, error) {
+	if !u.Enabled() {
+		return nil, ErrLoginNotConfigured
+	}
+	return u.oauth.RequestDeviceCode(ctx, u.scopes)
+}
+
+// CompleteLogin はユーザーが認可するまで待ち、取得したトークンを保存します
+func (u *LoginUsecase) CompleteLogin(ctx context.Context, guildID, channelID, userID string, code *github.DeviceCode) error {
+	if !u.Enabled() {
+		return ErrLoginNotConfigured
+	}
+
+	token, err := u.oauth.PollAccessToken(ctx, code)
+	if err != nil {
+		return err
+	}
+	return u.settings.SaveOAuthToken(ctx, guildID, channelID, userID, token)
+}
+
+// needsRefresh はアクセストークンの期限が近く、更新が必要かを返します
+func needsRefresh(setting *entity.UserSetting) bool {
+	return !setting.TokenExpiresAt.IsZero() && time.Until(setting.TokenExpiresAt) < oauthTokenRefreshMargin
+}
+
+// refreshIfExpired はアクセストークンの期限が近い場合にリフレッシュトークンで更新し、有効なトークンを返します。
+// 期限のないトークンは token をそのまま返し、更新できないトークンは期限を過ぎるまでそのまま使います。
+func (u *LoginUsecase) refreshIfExpired(ctx context.Context, setting *entity.UserSetting, token string) (string, error) {
+	if !needsRefresh(setting) {
+		return token, nil
+	}
+	if !u.Enabled() || setting.EncryptedRefreshToken == "" {
+		if !time.Now().After(setting.TokenExpiresAt) {
+			return token, nil
+		}
+		// 期限付きの PAT は更新できないため、再登録してもらう
+		if setting.EncryptedRefreshToken == "" {
+			return "", ErrTokenExpired
+		}
+		return "", ErrLoginExpired
+	}
+
+	u.refreshMu.Lock()
+	defer u.refreshMu.Unlock()
+
+	// 待っている間に別のコマンドが更新している場合は、保存済みのトークンを使う
+	latest, err := u.settings.repo.FindByGuildAndUser(ctx, setting.GuildID, setting.UserID)
+	if err != nil {
+		return "", err
+	}
+	if latest == nil || latest.EncryptedToken == "" {
+		return "", ErrTokenNotFound
+	}
+	if !needsRefresh(latest) {
+		*setting = *latest
+		return u.settings.crypto.Decrypt(latest.EncryptedToken)
+	}
+
+	refreshToken, err := u.settings.crypto.Decrypt(latest.EncryptedRefreshToken)
+	if err != nil {
+		return "", ErrTokenNotFound
+	}
+	refreshed, err := u.oauth.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, github.ErrRefreshTokenInvalid) {
+			return "", ErrLoginExpired
+		}
+		return "", fmt.Errorf("failed to refresh oauth token: %w", err)
+	}
+	if err := u.settings.saveOAuthToken(ctx, latest, refreshed); err != nil {
+		return "", err
+	}
+
+	*setting = *latest
+	return refreshed.AccessToken, nil
+}
@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/crypto"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+type PullRequestsUsecase struct {
+	repo   repository.UserSettingRepository
+	crypto *crypto.AESCrypto
+	config IssuesConfig
+}
+
+func NewPullRequestsUsecase(repo repository.UserSettingRepository, crypto *crypto.AESCrypto, config IssuesConfig) *PullRequestsUsecase {
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultFetchConcurrency
+	}
+	return &PullRequestsUsecase{
+		repo:   repo,
+		crypto: crypto,
+		config: config,
+	}
+}
+
+// レビュー状況
+const (
+	ReviewDecisionApproved         = "approved"
+	ReviewDecisionChangesRequested = "changes_requested"
+	ReviewDecisionReviewRequired   = "review_required"
+)
+
+// CI の状態
+const (
+	CIStatusSuccess = "success"
+	CIStatusPending = "pending"
+	CIStatusFailure = "failure"
+	CIStatusNone    = "none"
+)
+
+// PullRequestDetail は Pull Request とレビュー状況・CI 状態をまとめたものです
+type PullRequestDetail struct {
+	github.PullRequest
+	ReviewDecision string // ReviewDecision* のいずれか
+	CIStatus       string // CIStatus* のいずれか
+}
+
+// PullRequestsResult は Pull Request 取得結果とエラー情報を保持します
+type PullRequestsResult struct {
+	PullRequests []PullRequestDetail
+	RateLimit    *github.RateLimitInfo
+	FailedRepos  []RepositoryError
+	Truncated    bool // タイムアウトや Rate Limit により途中までの結果である場合に true
+}
+
+// pullRequestRef は詳細を取得する Pull Request の参照です
+type pullRequestRef struct {
+	fullName string
+	number   int
+}
+
+// reviewRequestedQuery は自分にレビュー依頼が来ているオープンな Pull Request の検索クエリです
+const reviewRequestedQuery = "is:pr is:open review-requested:@me archived:false"
+
+func (u *PullRequestsUsecase) newClient(setting *entity.UserSetting, token string) *github.Client {
+	return github.NewClient(token, resolveBaseURL(setting, u.config.GitHubBaseURL), u.config.clientOptions(token)...)
+}
+
+// GetRepositoryPullRequests は指定リポジトリのオープンな Pull Request を取得します
+func (u *PullRequestsUsecase) GetRepositoryPullRequests(ctx context.Context, guildID, userID, owner, repo string) (*PullRequestsResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
+	setting, token, err := loadSettingAndToken(ctx, u.repo, u.crypto, u.config, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	client := u.newClient(setting, token)
+	pulls, rateLimit, err := client.GetAllRepositoryPullRequests(ctx, owner, repo)
+	if err != nil && !(isTruncationError(err) && len(pulls) > 0) {
+		return &PullRequestsResult{RateLimit: rateLimit}, err
+	}
+
+	result := u.enrich(ctx, client, pulls, rateLimit)
+	if err != nil {
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// GetUserPullRequests は指定ユーザー/Organization のリポジトリのオープンな Pull Request を取得します
+func (u *PullRequestsUsecase) GetUserPullRequests(ctx context.Context, guildID, userID, username string) (*PullRequestsResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
+	setting, token, err := loadSettingAndToken(ctx, u.repo, u.crypto, u.config, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	client := u.newClient(setting, token)
+	issues, rateLimit, err := searchIssuesByOwners(ctx, client, []string{username}, IssuesOptions{Type: github.ItemTypePullRequests})
+	if !errors.Is(err, github.ErrSearchResultLimitExceeded) {
+		issues = filterExcludedRepositories(issues, setting.ExcludedIssuesRepositories)
+		return u.fromSearchResults(ctx, client, issues, rateLimit, err)
+	}
+
+	// 検索結果が上限を超える場合はリポジトリごとの取得にフォールバックする
+	repos, rateLimit, err := client.GetAllSpecificUserRepositories(ctx, username)
+	if err != nil {
+		return &PullRequestsResult{RateLimit: rateLimit}, err
+	}
+	return u.crawl(ctx, client, repos, setting.ExcludedIssuesRepositories, rateLimit), nil
+}
+
+// GetAllRepositoriesPullRequests はアクセス可能な全リポジトリのオープンな Pull Request を取得します
+func (u *PullRequestsUsecase) GetAllRepositoriesPullRequests(ctx context.Context, guildID, userID string) (*PullRequestsResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
+	setting, token, err := loadSettingAndToken(ctx, u.repo, u.crypto, u.config, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	client := u.newClient(setting, token)
+	repos, rateLimit, err := listAccessibleRepositories(ctx, client, setting)
+	if err != nil {
+		return &PullRequestsResult{RateLimit: rateLimit}, err
+	}
+
+	searchResult, err := searchRepositoriesIssues(ctx, client, repos, setting.ExcludedIssuesRepositories, IssuesOptions{Type: github.ItemTypePullRequests})
+	if errors.Is(err, github.ErrSearchResultLimitExceeded) {
+		// 検索結果が上限を超える場合はリポジトリごとの取得にフォールバックする
+		return u.crawl(ctx, client, repos, setting.ExcludedIssuesRepositories, rateLimit), nil
+	}
+	if err != nil {
+		return &PullRequestsResult{RateLimit: searchResult.RateLimit}, err
+	}
+
+	result, err := u.fromSearchResults(ctx, client, searchResult.Issues, searchResult.RateLimit, nil)
+	if result != nil && searchResult.Truncated {
+		result.Truncated = true
+	}
+	return result, err
+}
+
+// crawl はリポジトリごとにオープンな Pull Request を並行して取得します
+func (u *PullRequestsUsecase) crawl(ctx context.Context, client *github.Client, repos []github.Repository, excludedRepos []string, rateLimit *github.RateLimitInfo) *PullRequestsResult {
+	targets := make([]github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if isRepositoryExcluded(repo.FullName, excludedRepos) || len(splitRepoFullName(repo.FullName)) != 2 {
+			continue
+		}
+		targets = append(targets, repo)
+	}
+
+	tracker := newRateLimitTracker(rateLimit)
+	type repoOutcome struct {
+		pulls []github.PullRequest
+		err   error
+	}
+	outcomes, done := fanOut(ctx, targets, u.config.Concurrency, tracker, func(ctx context.Context, repo github.Repository) repoOutcome {
+		parts := splitRepoFullName(repo.FullName)
+		pulls, rl, err := client.GetAllRepositoryPullRequests(ctx, parts[0], parts[1])
+		tracker.update(rl)
+		return repoOutcome{pulls: pulls, err: err}
+	})
+
+	var (
+		pulls       []github.PullRequest
+		failedRepos []RepositoryError
+		truncated   bool
+	)
+	for idx, outcome := range outcomes {
+		if !done[idx] || isTruncationError(outcome.err) {
+			if done[idx] {
+				pulls = append(pulls, outcome.pulls...)
+			}
+			truncated = true
+			continue
+		}
+		if outcome.err != nil {
+			failedRepos = append(failedRepos, RepositoryError{RepositoryName: targets[idx].FullName, Error: outcome.err})
+			continue
+		}
+		pulls = append(pulls, outcome.pulls...)
+	}
+
+	result := u.enrich(ctx, client, pulls, tracker.snapshot())
+	result.FailedRepos = append(failedRepos, result.FailedRepos...)
+	result.Truncated = result.Truncated || truncated
+	return result
+}
+
+// GetReviewRequestedPullRequests は自分にレビュー依頼が来ているオープンな Pull Request を取得します
+func (u *PullRequestsUsecase) GetReviewRequestedPullRequests(ctx context.Context, guildID, userID string) (*PullRequestsResult, error) {
+	ctx = u.config.withPageBudget(ctx)
+
+	setting, token, err := loadSettingAndToken(ctx, u.repo, u.crypto, u.config, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// review-requested:@me は GitHub ユーザーとして認証している場合のみ利用できる
+	if usesInstallationToken(setting) {
+		return nil, ErrPersonalTokenRequired
+	}
+
+	client := u.newClient(setting, token)
+	issues, rateLimit, err := client.SearchAllIssues(ctx, reviewRequestedQuery)
+	issues = filterExcludedRepositories(issues, setting.ExcludedIssuesRepositories)
+	return u.fromSearchResults(ctx, client, issues, rateLimit, err)
+}
+
+// fromSearchResults は検索結果の Pull Request について、詳細・レビュー状況・CI 状態を取得します
+func (u *PullRequestsUsecase) fromSearchResults(ctx context.Context, client *github.Client, issues []github.Issue, rateLimit *github.RateLimitInfo, searchErr error) (*PullRequestsResult, error) {
+	truncated := false
+	if searchErr != nil {
+		if !isTruncationError(searchErr) || len(issues) == 0 {
+			return &PullRequestsResult{RateLimit: rateLimit}, searchErr
+		}
+		truncated = true
+	}
+
+	refs := make([]pullRequestRef, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Repository == nil || !issue.IsPullRequest() {
+			continue
+		}
+		refs = append(refs, pullRequestRef{fullName: issue.Repository.FullName, number: issue.Number})
+	}
+
+	tracker := newRateLimitTracker(rateLimit)
+	type pullOutcome struct {
+		pull *github.PullRequest
+		err  error
+	}
+	outcomes, done := fanOut(ctx, refs, u.config.Concurrency, tracker, func(ctx context.Context, ref pullRequestRef) pullOutcome {
+		parts := splitRepoFullName(ref.fullName)
+		pull, rl, err := client.GetPullRequest(ctx, parts[0], parts[1], ref.number)
+		tracker.update(rl)
+		return pullOutcome{pull: pull, err: err}
+	})
+
+	result := &PullRequestsResult{}
+	var pulls []github.PullRequest
+	for idx, outcome := range outcomes {
+		if !done[idx] || isTruncationError(outcome.err) {
+			truncated = true
+			continue
+		}
+		if outcome.err != nil {
+			result.FailedRepos = append(result.FailedRepos, RepositoryError{
+				RepositoryName: fmt.Sprintf("%s#%d", refs[idx].fullName, refs[idx].number),
+				Error:          outcome.err,
+			})
+			continue
+		}
+		pulls = append(pulls, *outcome.pull)
+	}
+
+	enriched := u.enrich(ctx, client, pulls, tracker.snapshot())
+	enriched.FailedRepos = append(result.FailedRepos, enriched.FailedRepos...)
+	enriched.Truncated = enriched.Truncated || truncated
+	return enriched, nil
+}
+
+// enrich は各 Pull Request のレビュー状況と CI 状態を並行して取得し、更新日時の新しい順に並べます
+func (u *PullRequestsUsecase) enrich(ctx context.Context, client *github.Client, pulls []github.PullRequest, rateLimit *github.RateLimitInfo) *PullRequestsResult {
+	tracker := newRateLimitTracker(rateLimit)
+	outcomes, done := fanOut(ctx, pulls, u.config.Concurrency, tracker, func(ctx context.Context, pull github.PullRequest) PullRequestDetail {
+		detail := PullRequestDetail{PullRequest: pull, ReviewDecision: ReviewDecisionReviewRequired, CIStatus: CIStatusNone}
+		parts := splitRepoFullName(pull.RepositoryFullName())
+		if len(parts) != 2 {
+			return detail
+		}
+
+		// レビューや CI の取得に失敗しても一覧表示は継続する
+		if reviews, rl, err := client.GetPullRequestReviews(ctx, parts[0], parts[1], pull.Number); err == nil {
+			tracker.update(rl)
+			detail.ReviewDecision = reviewDecision(reviews)
+		}
+
+		if pull.Head.SHA != "" {
+			status, rl, statusErr := client.GetCombinedStatus(ctx, parts[0], parts[1], pull.Head.SHA)
+			tracker.update(rl)
+			checkRuns, rl, checksErr := client.GetCheckRuns(ctx, parts[0], parts[1], pull.Head.SHA)
+			tracker.update(rl)
+			if statusErr != nil {
+				status = nil
+			}
+			if checksErr != nil {
+				checkRuns = nil
+			}
+			detail.CIStatus = ciStatus(status, checkRuns)
+		}
+		return detail
+	})
+
+	result := &PullRequestsResult{PullRequests: make([]PullRequestDetail, 0, len(pulls))}
+	for idx, detail := range outcomes {
+		if !done[idx] {
+			result.Truncated = true
+			continue
+		}
+		result.PullRequests = append(result.PullRequests, detail)
+	}
+
+	sort.SliceStable(result.PullRequests, func(a, b int) bool {
+		return result.PullRequests[a].UpdatedAt.After(result.PullRequests[b].UpdatedAt)
+	})
+	result.RateLimit = tracker.snapshot()
+	return result
+}
+
+// reviewDecision はレビュアーごとの最新のレビューからレビュー状況を判定します。
+// 変更要求が1件でもあれば changes_requested、承認があれば approved、それ以外は review_required です。
+func reviewDecision(reviews []github.Review) string {
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[strings.ToLower(review.User.Login)] = review.State
+		}
+	}
+
+	approved := false
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return ReviewDecisionChangesRequested
+		case "APPROVED":
+			approved = true
+		}
+	}
+	if approved {
+		return ReviewDecisionApproved
+	}
+	return ReviewDecisionReviewRequired
+}
+
+// ciStatus はコミットステータスとチェック実行結果を1つの CI 状態にまとめます
+func ciStatus(status *github.CombinedStatus, checkRuns []github.CheckRun) string {
+	hasAny := false
+	pending := false
+
+	if status != nil && status.TotalCount > 0 {
+		hasAny = true
+		switch status.State {
+		case "failure", "error":
+			return CIStatusFailure
+		case "pending":
+			pending = true
+		}
+	}
+
+	for _, run := range checkRuns {
+		hasAny = true
+		if run.Status != "completed" {
+			pending = true
+			continue
+		}
+		switch run.Conclusion {
+		case "failure", "timed_out", "cancelled", "action_required", "startup_failure":
+			return CIStatusFailure
+		}
+	}
+
+	switch {
+	case !hasAny:
+		return CIStatusNone
+	case pending:
+		return CIStatusPending
+	default:
+		return CIStatusSuccess
+	}
+}
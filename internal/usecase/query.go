@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+const (
+	// MaxSavedQueriesPerUser は1ユーザーが1サーバーで保存できる条件の上限です（オートコンプリートの候補数の上限と同じ）
+	MaxSavedQueriesPerUser = 25
+	// MaxQueryNameLength は条件の名前の最大文字数です
+	MaxQueryNameLength = 32
+)
+
+var (
+	ErrInvalidQueryName  = errors.New("invalid query name")
+	ErrQueryLimitReached = errors.New("saved query limit reached")
+	ErrQueryNotFound     = errors.New("saved query not found")
+	ErrQueryRepository   = errors.New("repository is required for saved query")
+	// ErrInvalidSince は since の形式が不正な場合に返されます
+	ErrInvalidSince = errors.New("invalid since")
+)
+
+// sinceUnits は since の相対指定で使える単位です
+var sinceUnits = map[byte]time.Duration{
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// QueryUsecase は /query で保存する名前付きの /issues の条件を管理します
+type QueryUsecase struct {
+	repo      repository.SavedQueryRepository
+	snapshots repository.IssueSnapshotRepository
+}
+
+func NewQueryUsecase(repo repository.SavedQueryRepository, snapshots repository.IssueSnapshotRepository) *QueryUsecase {
+	return &QueryUsecase{repo: repo, snapshots: snapshots}
+}
+
+// Save は条件を検証して保存します。同じ名前の条件がある場合は上書きし、created は false になります。
+// 上書きでリポジトリ・条件が変わった場合は、定期ダイジェストの変更検知のスナップショットを削除します
+// （古い条件の結果と比較して、条件の変更による差分を変更として通知しないようにする）。
+func (u *QueryUsecase) Save(ctx context.Context, guildID, userID, name, repositoryName string, filter entity.QueryFilter) (savedQuery *entity.SavedQuery, created bool, err error) {
+	if !isValidQueryName(name) {
+		return nil, false, ErrInvalidQueryName
+	}
+	if repositoryName == "" {
+		return nil, false, ErrQueryRepository
+	}
+	now := time.Now()
+	if _, err := NewIssuesOptions(filter, now); err != nil {
+		return nil, false, err
+	}
+
+	existing, err := u.repo.FindByName(ctx, guildID, userID, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing == nil {
+		queries, err := u.repo.FindByUser(ctx, guildID, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(queries) >= MaxSavedQueriesPerUser {
+			return nil, false, ErrQueryLimitReached
+		}
+	}
+
+	savedQuery = &entity.SavedQuery{
+		GuildID:    guildID,
+		UserID:     userID,
+		Name:       name,
+		Repository: repositoryName,
+		Filter:     filter,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if existing != nil {
+		savedQuery.CreatedAt = existing.CreatedAt
+	}
+	if err := u.repo.Save(ctx, savedQuery); err != nil {
+		return nil, false, err
+	}
+	if existing != nil && (existing.Repository != repositoryName || !sameQueryFilter(existing.Filter, filter)) {
+		if err := u.snapshots.Delete(ctx, guildID, userID, QuerySnapshotScope(existing.ID)); err != nil {
+			return nil, false, err
+		}
+	}
+	return savedQuery, existing == nil, nil
+}
+
+// sameQueryFilter は2つの条件が同じかを返します（保存時と同じ JSON で比較し、nil と空のスライスを区別しない）
+func sameQueryFilter(a, b entity.QueryFilter) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJSON) == string(bJSON)
+}
+
+// Get は名前が一致する条件を返します。存在しない場合は ErrQueryNotFound を返します。
+func (u *QueryUsecase) Get(ctx context.Context, guildID, userID, name string) (*entity.SavedQuery, error) {
+	savedQuery, err := u.repo.FindByName(ctx, guildID, userID, name)
+	if err != nil {
+		return nil, err
+	}
+	if savedQuery == nil {
+		return nil, ErrQueryNotFound
+	}
+	return savedQuery, nil
+}
+
+// GetByID は ID が一致する条件を返します。存在しない場合は ErrQueryNotFound を返します。
+func (u *QueryUsecase) GetByID(ctx context.Context, guildID, userID string, id int64) (*entity.SavedQuery, error) {
+	savedQuery, err := u.repo.FindByID(ctx, guildID, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if savedQuery == nil {
+		return nil, ErrQueryNotFound
+	}
+	return savedQuery, nil
+}
+
+// List はユーザーが保存した条件を名前順に返します
+func (u *QueryUsecase) List(ctx context.Context, guildID, userID string) ([]*entity.SavedQuery, error) {
+	return u.repo.FindByUser(ctx, guildID, userID)
+}
+
+// Delete はユーザーが保存した条件を削除します。存在しない場合は ErrQueryNotFound を返します。
+// 条件を使う定期ダイジェストはデータベースの外部キーにより一緒に削除されます。
+func (u *QueryUsecase) Delete(ctx context.Context, guildID, userID, name string) error {
+	deleted, err := u.repo.Delete(ctx, guildID, userID, name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrQueryNotFound
+	}
+	return nil
+}
+
+// Complete は名前のオートコンプリート候補を返します。
+// 入力で始まる名前を先に、入力を含む名前を後に並べます（大文字・小文字は区別しない）。
+func (u *QueryUsecase) Complete(ctx context.Context, guildID, userID, input string) ([]string, error) {
+	queries, err := u.repo.FindByUser(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	input = strings.ToLower(strings.TrimSpace(input))
+	var prefixed, contained []string
+	for _, savedQuery := range queries {
+		name := strings.ToLower(savedQuery.Name)
+		switch {
+		case strings.HasPrefix(name, input):
+			prefixed = append(prefixed, savedQuery.Name)
+		case strings.Contains(name, input):
+			contained = append(contained, savedQuery.Name)
+		}
+	}
+	sort.Strings(prefixed)
+	sort.Strings(contained)
+	names := append(prefixed, contained...)
+	if len(names) > MaxSavedQueriesPerUser {
+		names = names[:MaxSavedQueriesPerUser]
+	}
+	return names, nil
+}
+
+// isValidQueryName は名前が空白を含まない MaxQueryNameLength 文字以内かを判定します
+func isValidQueryName(name string) bool {
+	if name == "" || utf8.RuneCountInString(name) > MaxQueryNameLength {
+		return false
+	}
+	return strings.IndexFunc(name, unicode.IsSpace) < 0
+}
+
+// NewIssuesOptions は /issues のオプション・保存した条件を取得時の絞り込み条件に変換します。
+// since の相対指定は now を基準に解釈し、形式が不正な場合は ErrInvalidSince を返します。
+func NewIssuesOptions(filter entity.QueryFilter, now time.Time) (IssuesOptions, error) {
+	opts := IssuesOptions{
+		Type: github.ItemType(filter.Type),
+		Filter: github.IssueFilter{
+			State:         github.IssueState(filter.State),
+			Labels:        filter.Labels,
+			ExcludeLabels: filter.ExcludeLabels,
+			Milestone:     filter.Milestone,
+			Assignee:      filter.Assignee,
+			Creator:       filter.Creator,
+			Mentioned:     filter.Mentioned,
+			Sort:          github.IssueSort(filter.Sort),
+			Direction:     filter.Direction,
+		},
+		GroupBy: GroupBy(filter.GroupBy),
+	}
+	switch opts.Type {
+	case "", github.ItemTypeIssues, github.ItemTypePullRequests, github.ItemTypeBoth:
+	default:
+		return IssuesOptions{}, fmt.Errorf("invalid type: %s", filter.Type)
+	}
+	switch opts.Filter.State {
+	case "", github.IssueStateOpen, github.IssueStateClosed, github.IssueStateAll:
+	default:
+		return IssuesOptions{}, fmt.Errorf("invalid state: %s", filter.State)
+	}
+	switch opts.Filter.Sort {
+	case "", github.IssueSortCreated, github.IssueSortUpdated, github.IssueSortComments, github.IssueSortAge:
+	default:
+		return IssuesOptions{}, fmt.Errorf("invalid sort: %s", filter.Sort)
+	}
+	switch opts.GroupBy {
+	case GroupByNone, GroupByRepository, GroupByLabel, GroupByAssignee, GroupByMilestone:
+	default:
+		return IssuesOptions{}, fmt.Errorf("invalid group_by: %s", filter.GroupBy)
+	}
+
+	since, err := ParseSince(filter.Since, now)
+	if err != nil {
+		return IssuesOptions{}, err
+	}
+	opts.Filter.Since = since
+	return opts, nil
+}
+
+// ParseSince は since を日時に変換します（空の場合はゼロ値）。
+// 日付 (2024-04-01)・日時 (RFC 3339)・now からの相対指定 (7d, 24h, 2w) に対応します。
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(value)); err == nil {
+		return t, nil
+	}
+	if unit, ok := sinceUnits[value[len(value)-1]]; ok {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n > 0 {
+			return now.Add(-time.Duration(n) * unit), nil
+		}
+	}
+	return time.Time{}, ErrInvalidSince
+}
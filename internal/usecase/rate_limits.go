@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// rateLimitStateIdleTTL は使われなくなったトークンの Rate Limit の状態を破棄するまでの時間です。
+// GitHub の Rate Limit は1時間でリセットされるため、それ以上保持する必要はありません。
+const rateLimitStateIdleTTL = time.Hour
+
+// RateLimitStates はトークンごとに GitHub API の Rate Limit の状態を保持します。
+// クライアントはコマンドごとに生成するため、使い切った Rate Limit をここで次のコマンドに引き継ぎ、
+// リセットまではリクエストを送らずに失敗させます。
+type RateLimitStates struct {
+	mu      sync.Mutex
+	entries map[string]*rateLimitStateEntry
+}
+
+type rateLimitStateEntry struct {
+	state  *github.RateLimitState
+	usedAt time.Time
+}
+
+func NewRateLimitStates() *RateLimitStates {
+	return &RateLimitStates{entries: make(map[string]*rateLimitStateEntry)}
+}
+
+// forToken はトークンの Rate Limit の状態を返します。トークンはハッシュ化したものをキーにします。
+// 一定時間使われていないトークンの状態もここで破棄します。
+func (s *RateLimitStates) forToken(token string) *github.RateLimitState {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range s.entries {
+		if now.Sub(entry.usedAt) > rateLimitStateIdleTTL {
+			delete(s.entries, k)
+		}
+	}
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &rateLimitStateEntry{state: &github.RateLimitState{}}
+		s.entries[key] = entry
+	}
+	entry.usedAt = now
+	return entry.state
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/cron"
+)
+
+// 定期ダイジェストの対象
+const (
+	ScheduleScopeIssues = "issues"
+	ScheduleScopeAssign = "assign"
+	ScheduleScopeQuery  = "query" // /query で保存した条件
+)
+
+// 定期ダイジェストの投稿内容
+const (
+	ScheduleModeFull    = "full"    // 毎回すべての Issue を投稿する
+	ScheduleModeChanges = "changes" // 前回との差分（新規・クローズ・割り当て・ラベル変更）だけを投稿する
+)
+
+const (
+	// DefaultScheduleTimezone はタイムゾーンを指定しなかった場合に使うタイムゾーンです
+	DefaultScheduleTimezone = "Asia/Tokyo"
+	// MaxSchedulesPerUser は1ユーザーが1サーバーで登録できる定期ダイジェストの上限です
+	MaxSchedulesPerUser = 10
+	// MinScheduleInterval は定期ダイジェストの実行間隔の下限です（通知チャンネルの大量投稿を防ぐため）
+	MinScheduleInterval = time.Hour
+	// scheduleIntervalSamples は実行間隔を確認する回数です
+	scheduleIntervalSamples = 10
+)
+
+var (
+	ErrInvalidSchedule           = errors.New("invalid schedule")
+	ErrInvalidTimezone           = errors.New("invalid timezone")
+	ErrScheduleTooFrequent       = errors.New("schedule runs too frequently")
+	ErrScheduleLimitReached      = errors.New("schedule limit reached")
+	ErrScheduleNotFound          = errors.New("schedule not found")
+	ErrScheduleRepositoryMissing = errors.New("repository is required for issues schedule")
+	ErrScheduleQueryMissing      = errors.New("saved query is required for query schedule")
+)
+
+// ScheduleUsecase は /schedule で登録する定期ダイジェストを管理します。
+// 実行は cmd/bot のスケジューラが DueDigests と MarkRun を使って行います。
+type ScheduleUsecase struct {
+	repo repository.ScheduledDigestRepository
+}
+
+func NewScheduleUsecase(repo repository.ScheduledDigestRepository) *ScheduleUsecase {
+	return &ScheduleUsecase{repo: repo}
+}
+
+// Create は cron 式とタイムゾーンを検証し、定期ダイジェストを登録します。
+// repositoryName は scope が issues の場合、queryID は scope が query の場合にだけ使います。
+// mode が空の場合は ScheduleModeFull、timezone が空の場合は DefaultScheduleTimezone を利用します。
+func (u *ScheduleUsecase) Create(ctx context.Context, guildID, channelID, userID, scope, repositoryName string, queryID int64, mode, cronExpr, timezone string) (*entity.ScheduledDigest, error) {
+	if scope != ScheduleScopeIssues && scope != ScheduleScopeAssign && scope != ScheduleScopeQuery {
+		return nil, fmt.Errorf("invalid scope: %s (must be 'issues', 'assign' or 'query')", scope)
+	}
+	if mode == "" {
+		mode = ScheduleModeFull
+	}
+	if mode != ScheduleModeFull && mode != ScheduleModeChanges {
+		return nil, fmt.Errorf("invalid mode: %s (must be 'full' or 'changes')", mode)
+	}
+	if scope == ScheduleScopeIssues && repositoryName == "" {
+		return nil, ErrScheduleRepositoryMissing
+	}
+	if scope == ScheduleScopeQuery && queryID <= 0 {
+		return nil, ErrScheduleQueryMissing
+	}
+	if scope != ScheduleScopeIssues {
+		repositoryName = ""
+	}
+	if scope != ScheduleScopeQuery {
+		queryID = 0
+	}
+	if timezone == "" {
+		timezone = DefaultScheduleTimezone
+	}
+
+	schedule, loc, err := parseSchedule(cronExpr, timezone)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := validateScheduleInterval(schedule, now.In(loc)); err != nil {
+		return nil, err
+	}
+
+	existing, err := u.repo.FindByUser(ctx, guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxSchedulesPerUser {
+		return nil, ErrScheduleLimitReached
+	}
+
+	digest := &entity.ScheduledDigest{
+		GuildID:    guildID,
+		UserID:     userID,
+		ChannelID:  channelID,
+		Scope:      scope,
+		Repository: repositoryName,
+		QueryID:    queryID,
+		Mode:       mode,
+		CronExpr:   cronExpr,
+		Timezone:   timezone,
+		NextRunAt:  schedule.Next(now.In(loc)),
+		CreatedAt:  now,
+	}
+	if err := u.repo.Create(ctx, digest); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// List はユーザーが登録した定期ダイジェストを返します
+func (u *ScheduleUsecase) List(ctx context.Context, guildID, userID string) ([]*entity.ScheduledDigest, error) {
+	return u.repo.FindByUser(ctx, guildID, userID)
+}
+
+// Delete はユーザーが登録した定期ダイジェストを削除します。存在しない場合は ErrScheduleNotFound を返します。
+func (u *ScheduleUsecase) Delete(ctx context.Context, guildID, userID string, id int64) error {
+	deleted, err := u.repo.Delete(ctx, guildID, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// DueDigests は実行時刻を過ぎた定期ダイジェストを返します
+func (u *ScheduleUsecase) DueDigests(ctx context.Context, now time.Time) ([]*entity.ScheduledDigest, error) {
+	return u.repo.FindDue(ctx, now)
+}
+
+// MarkRun は実行済みとして記録し、次回の実行時刻を設定します。
+// Bot の停止中に過ぎた回はまとめて1回として扱い、ranAt より後の時刻を次回にします。
+func (u *ScheduleUsecase) MarkRun(ctx context.Context, digest *entity.ScheduledDigest, ranAt time.Time) error {
+	schedule, loc, err := parseSchedule(digest.CronExpr, digest.Timezone)
+	if err != nil {
+		return err
+	}
+	next := schedule.Next(ranAt.In(loc))
+	if next.IsZero() {
+		// 一致する日付がなくなった場合は実行しない（削除されるまで残す）
+		next = ranAt.AddDate(100, 0, 0)
+	}
+	digest.LastRunAt = ranAt
+	digest.NextRunAt = next
+	return u.repo.UpdateRun(ctx, digest.ID, ranAt, next)
+}
+
+// NextRunIn は定期ダイジェストの次回実行時刻を登録時のタイムゾーンで返します
+func NextRunIn(digest *entity.ScheduledDigest) time.Time {
+	if loc, err := time.LoadLocation(digest.Timezone); err == nil {
+		return digest.NextRunAt.In(loc)
+	}
+	return digest.NextRunAt
+}
+
+// parseSchedule は cron 式とタイムゾーンを解析します
+func parseSchedule(cronExpr, timezone string) (*cron.Schedule, *time.Location, error) {
+	schedule, err := cron.Parse(cronExpr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, timezone)
+	}
+	return schedule, loc, nil
+}
+
+// validateScheduleInterval は直近の実行時刻の間隔が MinScheduleInterval 以上であることを確認します
+func validateScheduleInterval(schedule *cron.Schedule, now time.Time) error {
+	prev := schedule.Next(now)
+	if prev.IsZero() {
+		return fmt.Errorf("%w: no matching time", ErrInvalidSchedule)
+	}
+	for i := 0; i < scheduleIntervalSamples; i++ {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			return nil
+		}
+		if next.Sub(prev) < MinScheduleInterval {
+			return ErrScheduleTooFrequent
+		}
+		prev = next
+	}
+	return nil
+}
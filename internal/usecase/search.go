@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// Issue 取得方式
+const (
+	// IssuesStrategySearch は Search API で owner 単位にまとめて検索します（上限超過時はクロールにフォールバック）
+	IssuesStrategySearch = "search"
+	// IssuesStrategyCrawl はリポジトリ一覧を取得し、各リポジトリの Issue を個別に取得します
+	IssuesStrategyCrawl = "crawl"
+)
+
+// maxOwnersPerSearchQuery は1つの検索クエリに含める user: 修飾子の最大数です
+const maxOwnersPerSearchQuery = 20
+
+// searchIssuesQuery は絞り込み条件に一致する Issue を owner で絞り込む検索クエリを組み立てます
+func searchIssuesQuery(owners []string, opts IssuesOptions) string {
+	qualifiers := opts.Filter.SearchQualifiers()
+	if qualifier := opts.itemType().SearchQualifier(); qualifier != "" {
+		qualifiers = append(qualifiers, qualifier)
+	}
+	for _, owner := range owners {
+		qualifiers = append(qualifiers, "user:"+owner)
+	}
+	return strings.Join(qualifiers, " ")
+}
+
+// searchIssuesByOwners は owner ごとの検索クエリを実行し、結果を結合します。
+// いずれかのクエリが上限を超えた場合は github.ErrSearchResultLimitExceeded を返します。
+func searchIssuesByOwners(ctx context.Context, client *github.Client, owners []string, opts IssuesOptions) ([]github.Issue, *github.RateLimitInfo, error) {
+	var (
+		allIssues     []github.Issue
+		lastRateLimit *github.RateLimitInfo
+	)
+
+	for start := 0; start < len(owners); start += maxOwnersPerSearchQuery {
+		end := start + maxOwnersPerSearchQuery
+		if end > len(owners) {
+			end = len(owners)
+		}
+
+		issues, rateLimit, err := client.SearchAllIssues(ctx, searchIssuesQuery(owners[start:end], opts))
+		if rateLimit != nil {
+			lastRateLimit = rateLimit
+		}
+		allIssues = append(allIssues, issues...)
+		if err != nil {
+			return allIssues, lastRateLimit, err
+		}
+	}
+
+	return allIssues, lastRateLimit, nil
+}
+
+// searchRepositoriesIssues は repos に含まれるリポジトリの Issue を Search API で取得します。
+// 検索は owner 単位で行うため、repos に含まれないリポジトリの結果は取り除きます。
+func searchRepositoriesIssues(ctx context.Context, client *github.Client, repos []github.Repository, excludedRepos []string, opts IssuesOptions) (*IssuesResult, error) {
+	allowed := make(map[string]bool, len(repos))
+	ownerSet := make(map[string]bool)
+	for _, repo := range repos {
+		parts := splitRepoFullName(repo.FullName)
+		if len(parts) != 2 || isRepositoryExcluded(repo.FullName, excludedRepos) {
+			continue
+		}
+		allowed[strings.ToLower(repo.FullName)] = true
+		ownerSet[parts[0]] = true
+	}
+
+	owners := make([]string, 0, len(ownerSet))
+	for owner := range ownerSet {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	issues, rateLimit, err := searchIssuesByOwners(ctx, client, owners, opts)
+
+	filtered := make([]github.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Repository != nil && allowed[strings.ToLower(issue.Repository.FullName)] {
+			filtered = append(filtered, issue)
+		}
+	}
+
+	return partialResult(filtered, rateLimit, err)
+}
+
+// searchOwnerIssues は指定ユーザー/Organization の Issue を Search API で取得します
+func searchOwnerIssues(ctx context.Context, client *github.Client, owner string, excludedRepos []string, opts IssuesOptions) (*IssuesResult, error) {
+	issues, rateLimit, err := searchIssuesByOwners(ctx, client, []string{owner}, opts)
+	filtered := filterExcludedRepositories(issues, excludedRepos)
+	return partialResult(filtered, rateLimit, err)
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github-discord-bot/internal/domain/entity"
@@ -11,15 +12,29 @@ import (
 	"github-discord-bot/internal/infrastructure/github"
 )
 
+// RequiredTokenScopes は PAT の登録に必要なスコープです（プライベートリポジトリと Organization の Issue の取得に使います）
+var RequiredTokenScopes = []string{"repo", "read:org"}
+
+// MissingScopesError は登録しようとした PAT に必要なスコープが不足している場合に返されます
+type MissingScopesError struct {
+	Missing []string
+}
+
+func (e *MissingScopesError) Error() string {
+	return fmt.Sprintf("token is missing required scopes: %s", strings.Join(e.Missing, ", "))
+}
+
 type SettingUsecase struct {
-	repo   repository.UserSettingRepository
-	crypto *crypto.AESCrypto
+	repo          repository.UserSettingRepository
+	crypto        *crypto.AESCrypto
+	githubBaseURL string
 }
 
-func NewSettingUsecase(repo repository.UserSettingRepository, crypto *crypto.AESCrypto) *SettingUsecase {
+func NewSettingUsecase(repo repository.UserSettingRepository, crypto *crypto.AESCrypto, githubBaseURL string) *SettingUsecase {
 	return &SettingUsecase{
-		repo:   repo,
-		crypto: crypto,
+		repo:          repo,
+		crypto:        crypto,
+		githubBaseURL: githubBaseURL,
 	}
 }
 
@@ -46,12 +61,25 @@ func validateIssuesOrAssignType(commandType string) error {
 	return nil
 }
 
-func (u *SettingUsecase) SaveToken(ctx context.Context, guildID, channelID, userID, token string) error {
+// SaveToken はトークンを検証して暗号化保存します。
+// baseURL にはトークンが属する GitHub API のベースURLを指定し、空の場合は既定のホストを利用します。
+// スコープを判別できるトークンで RequiredTokenScopes が不足している場合は MissingScopesError を返します。
+func (u *SettingUsecase) SaveToken(ctx context.Context, guildID, channelID, userID, token, baseURL string) error {
+	normalizedBaseURL, err := github.NormalizeBaseURL(baseURL)
+	if err != nil {
+		return err
+	}
+
 	// Validate token with GitHub API
-	client := github.NewClient(token)
-	if err := client.ValidateToken(); err != nil {
+	setting := &entity.UserSetting{GitHubBaseURL: normalizedBaseURL}
+	client := github.NewClient(token, resolveBaseURL(setting, u.githubBaseURL))
+	info, err := client.ValidateToken(ctx)
+	if err != nil {
 		return err
 	}
+	if missing := info.MissingScopes(RequiredTokenScopes); len(missing) > 0 {
+		return &MissingScopesError{Missing: missing}
+	}
 
 	// Encrypt token
 	encrypted, err := u.crypto.Encrypt(token)
@@ -59,13 +87,57 @@ func (u *SettingUsecase) SaveToken(ctx context.Context, guildID, channelID, user
 		return err
 	}
 
+	setting.GuildID = guildID
+	setting.ChannelID = channelID
+	setting.UserID = userID
+	setting.EncryptedToken = encrypted
+	setting.TokenExpiresAt = info.ExpiresAt
+	setting.TokenLogin = info.Login
+	setting.TokenScopes = info.Scopes
+	setting.UpdatedAt = time.Now()
+
+	return u.repo.Save(ctx, setting)
+}
+
+// SaveOAuthToken は /login で取得したアクセストークンを検証し、リフレッシュトークンとともに暗号化保存します。
+// OAuth のトークンは既定の GitHub API (GITHUB_API_BASE_URL) に属します。
+func (u *SettingUsecase) SaveOAuthToken(ctx context.Context, guildID, channelID, userID string, token *github.OAuthToken) error {
+	client := github.NewClient(token.AccessToken, u.githubBaseURL)
+	info, err := client.ValidateToken(ctx)
+	if err != nil {
+		return err
+	}
+
 	setting := &entity.UserSetting{
-		GuildID:        guildID,
-		ChannelID:      channelID,
-		UserID:         userID,
-		EncryptedToken: encrypted,
-		UpdatedAt:      time.Now(),
+		GuildID:     guildID,
+		ChannelID:   channelID,
+		UserID:      userID,
+		TokenLogin:  info.Login,
+		TokenScopes: info.Scopes,
 	}
+	return u.saveOAuthToken(ctx, setting, token)
+}
+
+// saveOAuthToken はアクセストークンとリフレッシュトークンを暗号化して setting に設定し、保存します
+func (u *SettingUsecase) saveOAuthToken(ctx context.Context, setting *entity.UserSetting, token *github.OAuthToken) error {
+	encrypted, err := u.crypto.Encrypt(token.AccessToken)
+	if err != nil {
+		return err
+	}
+	encryptedRefresh := ""
+	if token.RefreshToken != "" {
+		encryptedRefresh, err = u.crypto.Encrypt(token.RefreshToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	setting.EncryptedToken = encrypted
+	setting.EncryptedRefreshToken = encryptedRefresh
+	setting.TokenExpiresAt = token.ExpiresAt
+	setting.RefreshTokenExpiresAt = token.RefreshTokenExpiresAt
+	setting.GitHubBaseURL = ""
+	setting.UpdatedAt = time.Now()
 
 	return u.repo.Save(ctx, setting)
 }
@@ -148,6 +220,20 @@ func (u *SettingUsecase) SaveExcludedRepositories(ctx context.Context, guildID,
 	return u.repo.Save(ctx, setting)
 }
 
+// SaveRenderMode は一覧の既定の表示形式を保存します
+func (u *SettingUsecase) SaveRenderMode(ctx context.Context, guildID, channelID, userID, mode string) error {
+	setting, err := u.repo.FindByGuildAndUser(ctx, guildID, userID)
+	if err != nil {
+		return err
+	}
+
+	setting = ensureSettingWithChannel(setting, guildID, channelID, userID)
+	setting.RenderMode = mode
+	setting.UpdatedAt = time.Now()
+
+	return u.repo.Save(ctx, setting)
+}
+
 func (u *SettingUsecase) GetExcludedRepositories(ctx context.Context, guildID, userID string, commandType string) ([]string, error) {
 	if err := validateIssuesOrAssignType(commandType); err != nil {
 		return nil, err
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// フィードで投稿するイベント
+const (
+	FeedEventIssueOpened   = "issue_opened"   // issues: opened / reopened
+	FeedEventIssueClosed   = "issue_closed"   // issues: closed
+	FeedEventIssueLabeled  = "issue_labeled"  // issues: labeled
+	FeedEventIssueAssigned = "issue_assigned" // issues: assigned
+	FeedEventComment       = "comment"        // issue_comment: created
+	FeedEventPROpened      = "pr_opened"      // pull_request: opened / reopened / ready_for_review
+	FeedEventPRClosed      = "pr_closed"      // pull_request: closed（マージを含む）
+	FeedEventPRReview      = "pr_review"      // pull_request: review_requested / pull_request_review: submitted
+)
+
+// FeedEvents は /subscribe で指定できるイベントです（表示順）
+var FeedEvents = []string{
+	FeedEventIssueOpened,
+	FeedEventIssueClosed,
+	FeedEventIssueLabeled,
+	FeedEventIssueAssigned,
+	FeedEventComment,
+	FeedEventPROpened,
+	FeedEventPRClosed,
+	FeedEventPRReview,
+}
+
+// DefaultFeedEvents はイベントを指定しなかった場合に投稿するイベントです
+var DefaultFeedEvents = []string{FeedEventIssueOpened}
+
+// MaxChannelSubscriptionsPerGuild は1サーバーで登録できるフィードの上限です
+const MaxChannelSubscriptionsPerGuild = 50
+
+var (
+	ErrSubscriptionLimitReached = errors.New("channel subscription limit reached")
+	ErrSubscriptionNotFound     = errors.New("channel subscription not found")
+)
+
+// InvalidFeedEventError は存在しないイベントを指定した場合に返されます
+type InvalidFeedEventError struct {
+	Event string
+}
+
+func (e *InvalidFeedEventError) Error() string {
+	return fmt.Sprintf("invalid feed event: %s", e.Event)
+}
+
+// SubscriptionUsecase は /subscribe で登録するチャンネル単位のフィードを管理します。
+// フィードは webhook の配信から、リポジトリ・イベント・ラベルで絞り込んで投稿します。
+type SubscriptionUsecase struct {
+	repo repository.ChannelSubscriptionRepository
+}
+
+func NewSubscriptionUsecase(repo repository.ChannelSubscriptionRepository) *SubscriptionUsecase {
+	return &SubscriptionUsecase{repo: repo}
+}
+
+// Add はフィードを登録します。events が空の場合は DefaultFeedEvents を利用します。
+func (u *SubscriptionUsecase) Add(ctx context.Context, guildID, channelID, userID, repositoryPattern string, events, labels []string) (*entity.ChannelSubscription, error) {
+	events, err := normalizeFeedEvents(events)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := u.repo.FindByGuild(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxChannelSubscriptionsPerGuild {
+		return nil, ErrSubscriptionLimitReached
+	}
+
+	subscription := &entity.ChannelSubscription{
+		GuildID:           guildID,
+		ChannelID:         channelID,
+		RepositoryPattern: repositoryPattern,
+		Events:            events,
+		Labels:            labels,
+		CreatedBy:         userID,
+		CreatedAt:         time.Now(),
+	}
+	if err := u.repo.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// List はサーバーのフィードを返します
+func (u *SubscriptionUsecase) List(ctx context.Context, guildID string) ([]*entity.ChannelSubscription, error) {
+	return u.repo.FindByGuild(ctx, guildID)
+}
+
+// Remove はサーバーのフィードを削除します。存在しない場合は ErrSubscriptionNotFound を返します。
+func (u *SubscriptionUsecase) Remove(ctx context.Context, guildID string, id int64) error {
+	deleted, err := u.repo.Delete(ctx, guildID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// Match は webhook の配信に一致するフィードを返します。
+// guildIDs は配信の署名を検証できたサーバー、labels は絞り込みに使うラベル（labeled の場合は追加されたラベル）です。
+func (u *SubscriptionUsecase) Match(ctx context.Context, guildIDs []string, repositoryFullName, eventType, action string, labels []string) ([]*entity.ChannelSubscription, error) {
+	event := FeedEventOf(eventType, action)
+	if event == "" || len(guildIDs) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := u.repo.FindByRepository(ctx, guildIDs, repositoryFullName)
+	if err != nil {
+		return nil, err
+	}
+	var matched []*entity.ChannelSubscription
+	for _, subscription := range candidates {
+		if containsFold(subscription.Events, event) && labelsMatch(subscription.Labels, labels) {
+			matched = append(matched, subscription)
+		}
+	}
+	return matched, nil
+}
+
+// FeedEventOf は webhook のイベントと action をフィードのイベントに変換します。フィードで扱わない場合は空文字列を返します。
+func FeedEventOf(eventType, action string) string {
+	switch eventType {
+	case github.WebhookEventIssues:
+		switch action {
+		case "opened", "reopened":
+			return FeedEventIssueOpened
+		case "closed":
+			return FeedEventIssueClosed
+		case "labeled":
+			return FeedEventIssueLabeled
+		case "assigned":
+			return FeedEventIssueAssigned
+		}
+	case github.WebhookEventIssueComment:
+		if action == "created" {
+			return FeedEventComment
+		}
+	case github.WebhookEventPullRequest:
+		switch action {
+		case "opened", "reopened", "ready_for_review":
+			return FeedEventPROpened
+		case "closed":
+			return FeedEventPRClosed
+		case "review_requested":
+			return FeedEventPRReview
+		}
+	case github.WebhookEventPullRequestReview:
+		if action == "submitted" {
+			return FeedEventPRReview
+		}
+	}
+	return ""
+}
+
+// normalizeFeedEvents はイベントを検証し、重複を除いて返します
+func normalizeFeedEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return DefaultFeedEvents, nil
+	}
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !containsFold(FeedEvents, event) {
+			return nil, &InvalidFeedEventError{Event: event}
+		}
+		if !containsFold(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized, nil
+}
+
+// labelsMatch は filter が空か、labels が filter のいずれかを含む場合に true を返します
+func labelsMatch(filter, labels []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, label := range labels {
+		if containsFold(filter, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
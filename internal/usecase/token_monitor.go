@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/crypto"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// DefaultTokenExpiryWarning は期限切れの何日前に通知するかの既定値です
+const DefaultTokenExpiryWarning = 7 * 24 * time.Hour
+
+// TokenAlertKind はトークンの通知の種類です
+type TokenAlertKind string
+
+const (
+	TokenAlertExpiring TokenAlertKind = "expiring" // 期限切れが近い
+	TokenAlertExpired  TokenAlertKind = "expired"  // 期限切れ
+	TokenAlertRevoked  TokenAlertKind = "revoked"  // 取り消された（401）
+)
+
+// TokenAlert はユーザーに送るトークンの通知です
+type TokenAlert struct {
+	Kind      TokenAlertKind
+	Setting   *entity.UserSetting
+	ExpiresAt time.Time
+}
+
+// TokenAlertNotifier はトークンの通知をユーザーに届けます（DM または通知チャンネル）
+type TokenAlertNotifier interface {
+	NotifyTokenAlert(ctx context.Context, alert TokenAlert) error
+}
+
+// TokenMonitorUsecase は登録済みのトークンを定期的に確認し、期限切れ間近・期限切れ・取り消しを通知します
+type TokenMonitorUsecase struct {
+	repo          repository.UserSettingRepository
+	crypto        *crypto.AESCrypto
+	notifier      TokenAlertNotifier
+	config        IssuesConfig
+	expiryWarning time.Duration
+}
+
+// NewTokenMonitorUsecase は TokenMonitorUsecase を生成します。
+// expiryWarning が 0 以下の場合は DefaultTokenExpiryWarning を利用します。
+func NewTokenMonitorUsecase(repo repository.UserSettingRepository, crypto *crypto.AESCrypto, notifier TokenAlertNotifier, config IssuesConfig, expiryWarning time.Duration) *TokenMonitorUsecase {
+	if expiryWarning <= 0 {
+		expiryWarning = DefaultTokenExpiryWarning
+	}
+	return &TokenMonitorUsecase{
+		repo:          repo,
+		crypto:        crypto,
+		notifier:      notifier,
+		config:        config,
+		expiryWarning: expiryWarning,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに CheckAll を実行します
+func (u *TokenMonitorUsecase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.CheckAll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Token check failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll は有効なトークンをすべて確認します。ユーザーごとのエラーはログに記録して続行します。
+func (u *TokenMonitorUsecase) CheckAll(ctx context.Context) error {
+	settings, err := u.repo.FindAllWithValidToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range settings {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := u.check(ctx, setting, time.Now()); err != nil {
+			log.Printf("Token check failed for guild=%s user=%s: %v", setting.GuildID, setting.UserID, err)
+		}
+	}
+	return nil
+}
+
+// check は1ユーザーのトークンを確認し、必要に応じて通知して結果を保存します
+func (u *TokenMonitorUsecase) check(ctx context.Context, setting *entity.UserSetting, now time.Time) error {
+	token, err := u.crypto.Decrypt(setting.EncryptedToken)
+	if err != nil {
+		return err
+	}
+
+	// /login のトークンは期限が近ければ先に更新する（更新できなければ期限切れとして扱う）
+	token, err = u.config.Login.refreshIfExpired(ctx, setting, token)
+	switch {
+	case errors.Is(err, ErrTokenExpired), errors.Is(err, ErrLoginExpired):
+		return u.invalidate(ctx, setting, TokenAlertExpired, now)
+	case err != nil:
+		return err
+	}
+
+	// 期限を更新できないトークンは、期限の expiryWarning 前に1回だけ通知する
+	if setting.EncryptedRefreshToken == "" && !setting.TokenExpiresAt.IsZero() &&
+		setting.TokenExpiresAt.Sub(now) <= u.expiryWarning && setting.TokenExpiryNotifiedAt.IsZero() {
+		if err := u.notifier.NotifyTokenAlert(ctx, TokenAlert{Kind: TokenAlertExpiring, Setting: setting, ExpiresAt: setting.TokenExpiresAt}); err != nil {
+			return err
+		}
+		setting.TokenExpiryNotifiedAt = now
+	}
+
+	client := github.NewClient(token, resolveBaseURL(setting, u.config.GitHubBaseURL))
+	if _, err := client.ValidateToken(ctx); err != nil {
+		var ghErr *github.GitHubError
+		if errors.As(err, &ghErr) && ghErr.StatusCode == http.StatusUnauthorized {
+			return u.invalidate(ctx, setting, TokenAlertRevoked, now)
+		}
+		// 一時的なエラーは次回の確認に任せる
+		_, err := u.repo.UpdateTokenCheck(ctx, setting)
+		return err
+	}
+
+	setting.TokenCheckedAt = now
+	_, err = u.repo.UpdateTokenCheck(ctx, setting)
+	return err
+}
+
+// invalidate はトークンを無効として記録し、ユーザーに通知します
+func (u *TokenMonitorUsecase) invalidate(ctx context.Context, setting *entity.UserSetting, kind TokenAlertKind, now time.Time) error {
+	setting.TokenCheckedAt = now
+	setting.TokenInvalidAt = now
+	updated, err := u.repo.UpdateTokenCheck(ctx, setting)
+	if err != nil || !updated {
+		// 確認している間に登録し直されたトークンは無効として扱わない
+		return err
+	}
+	return u.notifier.NotifyTokenAlert(ctx, TokenAlert{Kind: kind, Setting: setting, ExpiresAt: setting.TokenExpiresAt})
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+// IssueChangeKind は前回のスナップショットからの変更の種類です
+type IssueChangeKind string
+
+const (
+	IssueChangeNew      IssueChangeKind = "new"      // 新しく一覧に現れた Issue
+	IssueChangeClosed   IssueChangeKind = "closed"   // 一覧から消えた Issue（クローズ・割り当て解除・除外など）
+	IssueChangeAssigned IssueChangeKind = "assigned" // 自分に新しく割り当てられた Issue
+	IssueChangeLabels   IssueChangeKind = "labels"   // ラベルが変更された Issue
+)
+
+// IssueChange は前回のスナップショットとの差分の1件です
+type IssueChange struct {
+	Kind          IssueChangeKind
+	Issue         entity.SnapshotIssue
+	AddedLabels   []string // Kind が IssueChangeLabels の場合のみ設定されます
+	RemovedLabels []string // Kind が IssueChangeLabels の場合のみ設定されます
+}
+
+// ChangeReport は変更検知の結果です
+type ChangeReport struct {
+	Changes  []IssueChange
+	Baseline bool // 前回のスナップショットがなく、今回の結果を記録しただけの場合に true
+	Total    int  // 今回取得した Issue の件数
+}
+
+// DiffOptions は DiffIssues の判定条件です
+type DiffOptions struct {
+	// Login は「自分への割り当て」の判定に使う GitHub のユーザー名です（空の場合は判定しない）
+	Login string
+	// AssignedOnly は自分に割り当てられた Issue だけの一覧（/assign）を比較する場合に true です。
+	// 新しく現れた Issue を IssueChangeAssigned として扱います。
+	AssignedOnly bool
+	// Complete は今回の結果がすべての Issue を含む場合に true です。
+	// 打ち切り・取得失敗がある場合は一覧から消えた Issue をクローズとして扱いません。
+	Complete bool
+}
+
+// WatchUsecase は取得結果を前回のスナップショットと比較し、変更された Issue だけを検出します
+type WatchUsecase struct {
+	snapshots repository.IssueSnapshotRepository
+}
+
+func NewWatchUsecase(snapshots repository.IssueSnapshotRepository) *WatchUsecase {
+	return &WatchUsecase{snapshots: snapshots}
+}
+
+// SnapshotScope は定期ダイジェストのスナップショットを区別するキーを返します
+func SnapshotScope(digest *entity.ScheduledDigest) string {
+	switch digest.Scope {
+	case ScheduleScopeAssign:
+		return ScheduleScopeAssign
+	case ScheduleScopeQuery:
+		return QuerySnapshotScope(digest.QueryID)
+	default:
+		return ScheduleScopeIssues + ":" + strings.ToLower(digest.Repository)
+	}
+}
+
+// QuerySnapshotScope は保存した条件の定期ダイジェストのスナップショットのキーを返します。
+// 条件を上書き保存しても ID は変わらないため、名前ではなく ID で区別します（条件が変わった場合は QueryUsecase.Save が削除します）。
+func QuerySnapshotScope(queryID int64) string {
+	return fmt.Sprintf("%s:%d", ScheduleScopeQuery, queryID)
+}
+
+// DetectChanges は取得結果を前回のスナップショットと比較して差分を返し、取得結果を新しいスナップショットとして保存します。
+// scope は SnapshotScope で生成したキーです。
+func (u *WatchUsecase) DetectChanges(ctx context.Context, guildID, userID, scope, login string, result *IssuesResult) (*ChangeReport, error) {
+	current := SnapshotIssues(result.Issues)
+	complete := !result.Truncated && len(result.FailedRepos) == 0
+
+	previous, err := u.snapshots.Find(ctx, guildID, userID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ChangeReport{Total: len(current)}
+	if previous == nil {
+		report.Baseline = true
+	} else {
+		report.Changes = DiffIssues(previous.Issues, current, DiffOptions{
+			Login:        login,
+			AssignedOnly: scope == ScheduleScopeAssign,
+			Complete:     complete,
+		})
+		if !complete {
+			// 取得できなかった Issue は次回の比較のために前回の内容を残す
+			current = carryOverIssues(previous.Issues, current)
+		}
+	}
+
+	err = u.snapshots.Save(ctx, &entity.IssueSnapshot{
+		GuildID:   guildID,
+		UserID:    userID,
+		Scope:     scope,
+		Issues:    current,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// SnapshotIssues は取得した Issue をスナップショットの形式に変換します
+func SnapshotIssues(issues []github.Issue) []entity.SnapshotIssue {
+	snapshot := make([]entity.SnapshotIssue, 0, len(issues))
+	for _, issue := range issues {
+		item := entity.SnapshotIssue{
+			URL:       issue.HTMLURL,
+			Number:    issue.Number,
+			Title:     issue.Title,
+			UpdatedAt: issue.UpdatedAt,
+		}
+		if issue.Repository != nil {
+			item.Repository = issue.Repository.FullName
+		}
+		for _, assignee := range issue.Assignees {
+			item.Assignees = append(item.Assignees, assignee.Login)
+		}
+		for _, label := range issue.Labels {
+			item.Labels = append(item.Labels, label.Name)
+		}
+		snapshot = append(snapshot, item)
+	}
+	return snapshot
+}
+
+// DiffIssues は前回と今回の Issue の一覧を比較し、変更を今回の一覧の順に返します。
+// 一覧から消えた Issue は最後にまとめて返します。
+func DiffIssues(previous, current []entity.SnapshotIssue, opts DiffOptions) []IssueChange {
+	previousByURL := make(map[string]entity.SnapshotIssue, len(previous))
+	for _, issue := range previous {
+		previousByURL[issue.URL] = issue
+	}
+
+	var changes []IssueChange
+	seen := make(map[string]bool, len(current))
+	for _, issue := range current {
+		seen[issue.URL] = true
+		before, ok := previousByURL[issue.URL]
+		if !ok {
+			kind := IssueChangeNew
+			if opts.AssignedOnly {
+				kind = IssueChangeAssigned
+			}
+			changes = append(changes, IssueChange{Kind: kind, Issue: issue})
+			continue
+		}
+		// 割り当て・ラベルの変更は updated_at も更新するため、変わっていなければ比較しない
+		if before.UpdatedAt.Equal(issue.UpdatedAt) {
+			continue
+		}
+
+		if !opts.AssignedOnly && opts.Login != "" && hasAssignee(issue, opts.Login) && !hasAssignee(before, opts.Login) {
+			changes = append(changes, IssueChange{Kind: IssueChangeAssigned, Issue: issue})
+		}
+		added, removed := diffStrings(before.Labels, issue.Labels)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, IssueChange{Kind: IssueChangeLabels, Issue: issue, AddedLabels: added, RemovedLabels: removed})
+		}
+	}
+
+	if opts.Complete {
+		for _, issue := range previous {
+			if !seen[issue.URL] {
+				changes = append(changes, IssueChange{Kind: IssueChangeClosed, Issue: issue})
+			}
+		}
+	}
+	return changes
+}
+
+// carryOverIssues は今回取得できなかった前回の Issue を今回の一覧に追加します
+func carryOverIssues(previous, current []entity.SnapshotIssue) []entity.SnapshotIssue {
+	seen := make(map[string]bool, len(current))
+	for _, issue := range current {
+		seen[issue.URL] = true
+	}
+	for _, issue := range previous {
+		if !seen[issue.URL] {
+			current = append(current, issue)
+		}
+	}
+	return current
+}
+
+func hasAssignee(issue entity.SnapshotIssue, login string) bool {
+	for _, assignee := range issue.Assignees {
+		if strings.EqualFold(assignee, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffStrings は before になく after にある値と、before にあり after にない値を返します
+func diffStrings(before, after []string) (added, removed []string) {
+	beforeSet := make(map[string]bool, len(before))
+	for _, v := range before {
+		beforeSet[v] = true
+	}
+	afterSet := make(map[string]bool, len(after))
+	for _, v := range after {
+		afterSet[v] = true
+		if !beforeSet[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range before {
+		if !afterSet[v] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+var (
+	watchTestBefore = time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)
+	watchTestAfter  = watchTestBefore.Add(time.Hour)
+)
+
+func snapshotIssue(number int, updatedAt time.Time, assignees, labels []string) entity.SnapshotIssue {
+	return entity.SnapshotIssue{
+		URL:        fmt.Sprintf("https://github.com/acme/api/issues/%d", number),
+		Repository: "acme/api",
+		Number:     number,
+		Title:      fmt.Sprintf("Issue %d", number),
+		UpdatedAt:  updatedAt,
+		Assignees:  assignees,
+		Labels:     labels,
+	}
+}
+
+// changeSummary は比較しやすいように IssueChange を "種類 #番号 +追加 -削除" の形式に変換します
+func changeSummary(changes []IssueChange) []string {
+	summary := []string{}
+	for _, change := range changes {
+		line := fmt.Sprintf("%s #%d", change.Kind, change.Issue.Number)
+		for _, label := range change.AddedLabels {
+			line += " +" + label
+		}
+		for _, label := range change.RemovedLabels {
+			line += " -" + label
+		}
+		summary = append(summary, line)
+	}
+	return summary
+}
+
+func TestDiffIssues(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []entity.SnapshotIssue
+		current  []entity.SnapshotIssue
+		opts     DiffOptions
+		want     []string
+	}{
+		{
+			name:     "unchanged",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, []string{"bug"})},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, []string{"bug"})},
+			opts:     DiffOptions{Complete: true},
+			want:     []string{},
+		},
+		{
+			name:     "new issue",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil)},
+			current:  []entity.SnapshotIssue{snapshotIssue(2, watchTestAfter, nil, nil), snapshotIssue(1, watchTestBefore, nil, nil)},
+			opts:     DiffOptions{Complete: true},
+			want:     []string{"new #2"},
+		},
+		{
+			name:     "new issue in assigned-only list is reported as assigned",
+			previous: []entity.SnapshotIssue{},
+			current:  []entity.SnapshotIssue{snapshotIssue(3, watchTestAfter, []string{"octocat"}, nil)},
+			opts:     DiffOptions{Login: "octocat", AssignedOnly: true, Complete: true},
+			want:     []string{"assigned #3"},
+		},
+		{
+			name:     "closed issue",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil), snapshotIssue(2, watchTestBefore, nil, nil)},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil)},
+			opts:     DiffOptions{Complete: true},
+			want:     []string{"closed #2"},
+		},
+		{
+			name:     "missing issue is not closed when the result is incomplete",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil), snapshotIssue(2, watchTestBefore, nil, nil)},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil)},
+			opts:     DiffOptions{Complete: false},
+			want:     []string{},
+		},
+		{
+			// クローズした Issue は前回のスナップショットから消えているため、再オープンされると新しい Issue として現れる
+			name:     "reopened issue",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil)},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil), snapshotIssue(4, watchTestAfter, nil, []string{"bug"})},
+			opts:     DiffOptions{Complete: true},
+			want:     []string{"new #4"},
+		},
+		{
+			name:     "labels changed",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, []string{"bug", "triage"})},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestAfter, nil, []string{"bug", "p1"})},
+			opts:     DiffOptions{Complete: true},
+			want:     []string{"labels #1 +p1 -triage"},
+		},
+		{
+			name:     "label change is ignored when updated_at is unchanged",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, []string{"bug"})},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, []string{"p1"})},
+			opts:     DiffOptions{Complete: true},
+			want:     []string{},
+		},
+		{
+			name:     "assigned to me",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, []string{"hubot"}, nil)},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestAfter, []string{"hubot", "OctoCat"}, nil)},
+			opts:     DiffOptions{Login: "octocat", Complete: true},
+			want:     []string{"assigned #1"},
+		},
+		{
+			name:     "assigned to someone else",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil)},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestAfter, []string{"hubot"}, nil)},
+			opts:     DiffOptions{Login: "octocat", Complete: true},
+			want:     []string{},
+		},
+		{
+			name:     "assignee and labels changed together",
+			previous: []entity.SnapshotIssue{snapshotIssue(1, watchTestBefore, nil, nil)},
+			current:  []entity.SnapshotIssue{snapshotIssue(1, watchTestAfter, []string{"octocat"}, []string{"bug"})},
+			opts:     DiffOptions{Login: "octocat", Complete: true},
+			want:     []string{"assigned #1", "labels #1 +bug"},
+		},
+		{
+			name: "changes follow the current order and closed issues come last",
+			previous: []entity.SnapshotIssue{
+				snapshotIssue(1, watchTestBefore, nil, nil),
+				snapshotIssue(2, watchTestBefore, nil, nil),
+				snapshotIssue(3, watchTestBefore, nil, nil),
+			},
+			current: []entity.SnapshotIssue{
+				snapshotIssue(3, watchTestAfter, nil, []string{"bug"}),
+				snapshotIssue(5, watchTestAfter, nil, nil),
+				snapshotIssue(1, watchTestBefore, nil, nil),
+			},
+			opts: DiffOptions{Complete: true},
+			want: []string{"labels #3 +bug", "new #5", "closed #2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := changeSummary(DiffIssues(tt.previous, tt.current, tt.opts))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffIssues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCarryOverIssues(t *testing.T) {
+	previous := []entity.SnapshotIssue{
+		snapshotIssue(1, watchTestBefore, nil, nil),
+		snapshotIssue(2, watchTestBefore, nil, []string{"bug"}),
+		snapshotIssue(3, watchTestBefore, nil, nil),
+	}
+	current := []entity.SnapshotIssue{
+		snapshotIssue(2, watchTestAfter, nil, []string{"p1"}),
+		snapshotIssue(4, watchTestAfter, nil, nil),
+	}
+
+	got := carryOverIssues(previous, current)
+
+	want := []entity.SnapshotIssue{
+		snapshotIssue(2, watchTestAfter, nil, []string{"p1"}), // 取得できた Issue は今回の内容を使う
+		snapshotIssue(4, watchTestAfter, nil, nil),
+		snapshotIssue(1, watchTestBefore, nil, nil),
+		snapshotIssue(3, watchTestBefore, nil, nil),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("carryOverIssues() = %+v, want %+v", got, want)
+	}
+}
+
+// memoryIssueSnapshotRepository はスナップショットをメモリ上に保持する repository.IssueSnapshotRepository です
+type memoryIssueSnapshotRepository struct {
+	snapshots map[string]*entity.IssueSnapshot
+}
+
+func (r *memoryIssueSnapshotRepository) Find(ctx context.Context, guildID, userID, scope string) (*entity.IssueSnapshot, error) {
+	return r.snapshots[guildID+"/"+userID+"/"+scope], nil
+}
+
+func (r *memoryIssueSnapshotRepository) Save(ctx context.Context, snapshot *entity.IssueSnapshot) error {
+	r.snapshots[snapshot.GuildID+"/"+snapshot.UserID+"/"+snapshot.Scope] = snapshot
+	return nil
+}
+
+func (r *memoryIssueSnapshotRepository) Delete(ctx context.Context, guildID, userID, scope string) error {
+	delete(r.snapshots, guildID+"/"+userID+"/"+scope)
+	return nil
+}
+
+func githubIssue(number int, updatedAt time.Time) github.Issue {
+	return github.Issue{
+		Number:     number,
+		Title:      fmt.Sprintf("Issue %d", number),
+		HTMLURL:    fmt.Sprintf("https://github.com/acme/api/issues/%d", number),
+		UpdatedAt:  updatedAt,
+		Repository: &github.Repository{FullName: "acme/api"},
+	}
+}
+
+// TestDetectChangesTruncated は途中までの結果では一覧から消えた Issue をクローズとして扱わず、
+// 次回の比較のためにスナップショットに残すことを確認します
+func TestDetectChangesTruncated(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryIssueSnapshotRepository{snapshots: make(map[string]*entity.IssueSnapshot)}
+	watch := NewWatchUsecase(repo)
+	scope := "issues:acme/api"
+
+	detect := func(result *IssuesResult) *ChangeReport {
+		t.Helper()
+		report, err := watch.DetectChanges(ctx, "guild-1", "user-1", scope, "octocat", result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+
+	report := detect(&IssuesResult{Issues: []github.Issue{githubIssue(1, watchTestBefore), githubIssue(2, watchTestBefore)}})
+	if !report.Baseline || len(report.Changes) != 0 || report.Total != 2 {
+		t.Fatalf("first run: got %+v, want a baseline with 2 issues", report)
+	}
+
+	// 打ち切られた結果では #2 が取得できていない
+	report = detect(&IssuesResult{Issues: []github.Issue{githubIssue(1, watchTestBefore), githubIssue(3, watchTestAfter)}, Truncated: true})
+	if got, want := changeSummary(report.Changes), []string{"new #3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("truncated run: changes = %q, want %q", got, want)
+	}
+	saved := repo.snapshots["guild-1/user-1/"+scope]
+	if got := len(saved.Issues); got != 3 {
+		t.Errorf("truncated run: saved %d issues, want 3 (#2 carried over)", got)
+	}
+
+	// 一部のリポジトリの取得に失敗した場合も同じく不完全として扱う
+	report = detect(&IssuesResult{Issues: []github.Issue{githubIssue(1, watchTestBefore)}, FailedRepos: []RepositoryError{{}}})
+	if got := changeSummary(report.Changes); len(got) != 0 {
+		t.Errorf("failed repos run: changes = %q, want none", got)
+	}
+
+	// すべて取得できた結果では、引き継いだ #2 と #3 がなければクローズとして扱う
+	report = detect(&IssuesResult{Issues: []github.Issue{githubIssue(1, watchTestBefore)}})
+	if got, want := changeSummary(report.Changes), []string{"closed #3", "closed #2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("complete run: changes = %q, want %q", got, want)
+	}
+}
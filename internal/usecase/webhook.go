@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github-discord-bot/internal/domain/entity"
+	"github-discord-bot/internal/domain/repository"
+	"github-discord-bot/internal/infrastructure/crypto"
+	"github-discord-bot/internal/infrastructure/github"
+)
+
+const (
+	// MaxWebhookSubscriptionsPerGuild は1サーバーで登録できる webhook の上限です
+	MaxWebhookSubscriptionsPerGuild = 25
+	// webhookSecretBytes は生成する webhook の Secret のバイト数です
+	webhookSecretBytes = 32
+)
+
+var (
+	// ErrWebhookNotConfigured は webhook の受信サーバーが起動していない場合に返されます
+	ErrWebhookNotConfigured = errors.New("webhook server is not configured")
+	ErrWebhookLimitReached  = errors.New("webhook subscription limit reached")
+	ErrWebhookNotFound      = errors.New("webhook subscription not found")
+	// ErrWebhookNotSubscribed は配信元のリポジトリに一致する登録がない場合に返されます
+	ErrWebhookNotSubscribed = errors.New("no webhook subscription for repository")
+	// ErrWebhookSignatureMismatch は一致する登録のどの Secret でも署名を検証できなかった場合に返されます
+	ErrWebhookSignatureMismatch = errors.New("webhook signature mismatch")
+)
+
+// WebhookUsecase は GitHub webhook の配信を投稿するチャンネルの登録と、配信の振り分けを行います
+type WebhookUsecase struct {
+	repo       repository.WebhookSubscriptionRepository
+	crypto     *crypto.AESCrypto
+	payloadURL string
+}
+
+// NewWebhookUsecase は WebhookUsecase を生成します。
+// payloadURL は GitHub に設定する webhook の Payload URL で、空の場合（受信サーバー未起動）は登録できません。
+func NewWebhookUsecase(repo repository.WebhookSubscriptionRepository, crypto *crypto.AESCrypto, payloadURL string) *WebhookUsecase {
+	return &WebhookUsecase{repo: repo, crypto: crypto, payloadURL: payloadURL}
+}
+
+// Enabled は webhook の受信サーバーが起動しているかを返します
+func (u *WebhookUsecase) Enabled() bool {
+	return u != nil && u.payloadURL != ""
+}
+
+// PayloadURL は GitHub に設定する webhook の Payload URL を返します
+func (u *WebhookUsecase) PayloadURL() string {
+	return u.payloadURL
+}
+
+// Subscribe は登録を作成し、生成した webhook の Secret を返します。
+// Secret は暗号化して保存するため、平文を確認できるのはこの戻り値だけです。
+func (u *WebhookUsecase) Subscribe(ctx context.Context, guildID, channelID, userID, repositoryPattern string) (*entity.WebhookSubscription, string, error) {
+	if !u.Enabled() {
+		return nil, "", ErrWebhookNotConfigured
+	}
+	existing, err := u.repo.FindByGuild(ctx, guildID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= MaxWebhookSubscriptionsPerGuild {
+		return nil, "", ErrWebhookLimitReached
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	encryptedSecret, err := u.crypto.Encrypt(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	subscription := &entity.WebhookSubscription{
+		GuildID:           guildID,
+		ChannelID:         channelID,
+		RepositoryPattern: repositoryPattern,
+		EncryptedSecret:   encryptedSecret,
+		CreatedBy:         userID,
+		CreatedAt:         time.Now(),
+	}
+	if err := u.repo.Create(ctx, subscription); err != nil {
+		return nil, "", err
+	}
+	return subscription, secret, nil
+}
+
+// List はサーバーの登録を返します
+func (u *WebhookUsecase) List(ctx context.Context, guildID string) ([]*entity.WebhookSubscription, error) {
+	return u.repo.FindByGuild(ctx, guildID)
+}
+
+// Unsubscribe はサーバーの登録を削除します。存在しない場合は ErrWebhookNotFound を返します。
+func (u *WebhookUsecase) Unsubscribe(ctx context.Context, guildID string, id int64) error {
+	deleted, err := u.repo.Delete(ctx, guildID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Route は配信元のリポジトリに一致する登録のうち、X-Hub-Signature-256 を Secret で検証できたものを返します。
+// 一致する登録がない場合は ErrWebhookNotSubscribed、どの Secret でも検証できない場合は ErrWebhookSignatureMismatch を返します。
+func (u *WebhookUsecase) Route(ctx context.Context, repositoryFullName string, payload []byte, signature string) ([]*entity.WebhookSubscription, error) {
+	candidates, err := u.repo.FindByRepository(ctx, repositoryFullName)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, ErrWebhookNotSubscribed
+	}
+
+	var verified []*entity.WebhookSubscription
+	for _, subscription := range candidates {
+		secret, err := u.crypto.Decrypt(subscription.EncryptedSecret)
+		if err != nil {
+			continue
+		}
+		if github.VerifyWebhookSignature(payload, signature, secret) {
+			verified = append(verified, subscription)
+		}
+	}
+	if len(verified) == 0 {
+		return nil, ErrWebhookSignatureMismatch
+	}
+	return verified, nil
+}
+
+// generateWebhookSecret はランダムな webhook の Secret を生成します
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
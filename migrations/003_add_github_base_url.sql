@@ -0,0 +1,3 @@
+-- トークンが属する GitHub API のベースURL (GitHub Enterprise Server 用)
+-- NULL の場合は環境変数 GITHUB_API_BASE_URL (未設定なら https://api.github.com) を利用する
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS github_base_url TEXT;
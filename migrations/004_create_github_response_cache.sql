@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS github_response_cache (
+    cache_key CHAR(64) PRIMARY KEY,
+    etag TEXT,
+    last_modified TEXT,
+    body BYTEA NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_response_cache_updated_at ON github_response_cache (updated_at);
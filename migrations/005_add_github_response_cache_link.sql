@@ -0,0 +1 @@
+ALTER TABLE github_response_cache ADD COLUMN IF NOT EXISTS link TEXT;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS guild_app_installations (
+    guild_id VARCHAR(32) PRIMARY KEY,
+    installation_id BIGINT NOT NULL,
+    account_login TEXT NOT NULL,
+    linked_by VARCHAR(32) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
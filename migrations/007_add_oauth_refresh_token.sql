@@ -0,0 +1,5 @@
+-- /login (OAuth デバイス認可フロー) で取得したトークンの更新用
+-- encrypted_refresh_token は AES-256-GCM で暗号化したリフレッシュトークン。期限のないトークン (PAT など) は NULL
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS encrypted_refresh_token TEXT;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS token_expires_at TIMESTAMP;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS refresh_token_expires_at TIMESTAMP;
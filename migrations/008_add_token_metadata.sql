@@ -0,0 +1,5 @@
+-- トークン登録時に取得した認証ユーザーとスコープ (/setting action:status で表示)
+-- token_scopes が NULL の場合はスコープを判別できないトークン (Fine-grained PAT など)
+-- PAT の有効期限は 007 で追加した token_expires_at に保存する
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS token_login TEXT;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS token_scopes TEXT[];
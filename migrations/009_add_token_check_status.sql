@@ -0,0 +1,6 @@
+-- トークンの定期確認 (期限切れ間近・取り消しの通知) の状態
+-- token_invalid_at が設定されたトークンはコマンドで利用せず、再登録を促す
+-- いずれもトークンを再登録すると NULL に戻る
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS token_checked_at TIMESTAMP;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS token_invalid_at TIMESTAMP;
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS token_expiry_notified_at TIMESTAMP;
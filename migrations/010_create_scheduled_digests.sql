@@ -0,0 +1,19 @@
+-- /schedule で登録した定期ダイジェスト
+-- next_run_at / last_run_at は UTC で保存する (cron 式は timezone のローカル時刻で評価する)
+CREATE TABLE IF NOT EXISTS scheduled_digests (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id VARCHAR(32) NOT NULL,
+    user_id VARCHAR(32) NOT NULL,
+    channel_id VARCHAR(32) NOT NULL,
+    scope VARCHAR(16) NOT NULL,
+    repository TEXT,
+    cron_expr TEXT NOT NULL,
+    timezone TEXT NOT NULL,
+    next_run_at TIMESTAMP NOT NULL,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (scope IN ('issues', 'assign'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_digests_next_run_at ON scheduled_digests (next_run_at);
+CREATE INDEX IF NOT EXISTS idx_scheduled_digests_user ON scheduled_digests (guild_id, user_id);
@@ -0,0 +1,15 @@
+-- 変更検知 (/schedule mode:changes) で前回取得した Issue の一覧
+-- scope は assign または issues:<repository> (guild_id・user_id ごとに対象を区別する)
+CREATE TABLE IF NOT EXISTS issue_snapshots (
+    guild_id VARCHAR(32) NOT NULL,
+    user_id VARCHAR(32) NOT NULL,
+    scope TEXT NOT NULL,
+    issues JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (guild_id, user_id, scope)
+);
+
+-- full: 毎回すべての Issue を投稿する / changes: 前回との差分だけを投稿する
+ALTER TABLE scheduled_digests ADD COLUMN IF NOT EXISTS mode VARCHAR(16) NOT NULL DEFAULT 'full';
+ALTER TABLE scheduled_digests DROP CONSTRAINT IF EXISTS scheduled_digests_mode_check;
+ALTER TABLE scheduled_digests ADD CONSTRAINT scheduled_digests_mode_check CHECK (mode IN ('full', 'changes'));
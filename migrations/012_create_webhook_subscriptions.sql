@@ -0,0 +1,14 @@
+-- GitHub webhook の配信を投稿するチャンネル
+-- repository_pattern は owner/repo・owner/*・owner のいずれか、encrypted_secret は webhook の Secret を暗号化したもの
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id VARCHAR(32) NOT NULL,
+    channel_id VARCHAR(32) NOT NULL,
+    repository_pattern TEXT NOT NULL,
+    encrypted_secret TEXT NOT NULL,
+    created_by VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_guild ON webhook_subscriptions (guild_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_pattern ON webhook_subscriptions (LOWER(repository_pattern));
@@ -0,0 +1,15 @@
+-- /subscribe で登録したチャンネル単位のフィード
+-- webhook の配信のうち、repository_pattern・events・labels に一致するものを channel_id に投稿する
+-- labels が空配列の場合はラベルで絞り込まない
+CREATE TABLE IF NOT EXISTS channel_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id VARCHAR(32) NOT NULL,
+    channel_id VARCHAR(32) NOT NULL,
+    repository_pattern TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    labels TEXT[] NOT NULL DEFAULT '{}',
+    created_by VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_subscriptions_guild ON channel_subscriptions (guild_id);
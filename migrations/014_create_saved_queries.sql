@@ -0,0 +1,18 @@
+-- /query で保存した名前付きの /issues の条件
+-- filter は絞り込み条件 (種類・状態・ラベルなど) の JSON。since は実行時に解釈するため入力した文字列のまま保存する
+CREATE TABLE IF NOT EXISTS saved_queries (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id VARCHAR(32) NOT NULL,
+    user_id VARCHAR(32) NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    repository TEXT NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (guild_id, user_id, name)
+);
+
+-- scope:query の定期ダイジェストは保存した条件で取得する (条件を削除すると定期ダイジェストも削除する)
+ALTER TABLE scheduled_digests ADD COLUMN IF NOT EXISTS query_id BIGINT REFERENCES saved_queries (id) ON DELETE CASCADE;
+ALTER TABLE scheduled_digests DROP CONSTRAINT IF EXISTS scheduled_digests_scope_check;
+ALTER TABLE scheduled_digests ADD CONSTRAINT scheduled_digests_scope_check CHECK (scope IN ('issues', 'assign', 'query'));
@@ -0,0 +1,3 @@
+-- 一覧の既定の表示形式 (rich / compact / auto)
+-- NULL の場合は rich。コマンドの format オプションで 1 回ごとに上書きできる
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS render_mode TEXT;